@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"travelmate-api/database"
+	"travelmate-api/middleware"
+	"travelmate-api/models"
+	"travelmate-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// Durée de validité d'un jeton d'API sans date d'expiration explicite
+	apiTokenDefaultTTL = 90 * 24 * time.Hour
+	// Durée de validité maximale d'un jeton d'API
+	apiTokenMaxTTL = 365 * 24 * time.Hour
+	// Nombre de caractères du jeton conservés en clair pour le reconnaître
+	apiTokenPrefixLength = len(utils.APITokenPrefix) + 4
+)
+
+// CreateAPIToken godoc
+// @Summary Créer un jeton d'API
+// @Description Crée un jeton d'accès personnel pour un script ou une intégration, à utiliser comme un jeton d'accès (Authorization: Bearer tm_pat_...). Il ne donne accès qu'aux routes couvertes par ses scopes (trips:read, trips:write, users:read), jamais à la gestion du compte ni à l'administration. Sans date d'expiration, il expire au bout de 90 jours ; au plus tard au bout d'un an. Le jeton n'est retourné qu'une seule fois.
+// @Tags API tokens
+// @Accept json
+// @Produce json
+// @Param input body object{name=string,scopes=[]string,expiresAt=string} true "Nom, scopes et date d'expiration"
+// @Success 201 {object} models.APIToken
+// @Failure 400 {object} map[string]string "Nom, scopes ou date d'expiration invalides"
+// @Failure 403 {object} map[string]string "Action indisponible avec un jeton d'API"
+// @Security BearerAuth
+// @Router /me/tokens [post]
+func CreateAPIToken(c *gin.Context) {
+	var input struct {
+		Name      string     `json:"name" binding:"required"`
+		Scopes    []string   `json:"scopes" binding:"required"`
+		ExpiresAt *time.Time `json:"expiresAt"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le nom et les scopes sont requis"})
+		return
+	}
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le nom est requis"})
+		return
+	}
+
+	scopes := []string{}
+	for _, scope := range input.Scopes {
+		if !slices.Contains(middleware.APITokenScopes, scope) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Scope inconnu : " + scope, "scopes": middleware.APITokenScopes})
+			return
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Au moins un scope est requis", "scopes": middleware.APITokenScopes})
+		return
+	}
+
+	expiresAt := time.Now().Add(apiTokenDefaultTTL)
+	if input.ExpiresAt != nil {
+		if input.ExpiresAt.Before(time.Now()) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "La date d'expiration doit être dans le futur"})
+			return
+		}
+		if input.ExpiresAt.After(time.Now().Add(apiTokenMaxTTL)) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "La date d'expiration ne peut pas dépasser un an"})
+			return
+		}
+		expiresAt = *input.ExpiresAt
+	}
+
+	token, err := utils.GenerateAPIToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la génération du token"})
+		return
+	}
+
+	apiToken := models.APIToken{
+		UserID:    c.GetUint("user_id"),
+		Name:      input.Name,
+		Prefix:    token[:apiTokenPrefixLength],
+		TokenHash: utils.HashToken(token),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}
+	if err := database.DB.Create(&apiToken).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la création du token"})
+		return
+	}
+
+	apiToken.Token = token
+	c.JSON(http.StatusCreated, apiToken)
+}
+
+// GetAPITokens godoc
+// @Summary Mes jetons d'API
+// @Description Retourne les jetons d'accès personnels actifs (ni révoqués ni expirés) de l'utilisateur, sans leur valeur, avec leur date de dernière utilisation.
+// @Tags API tokens
+// @Produce json
+// @Success 200 {array} models.APIToken
+// @Failure 403 {object} map[string]string "Action indisponible avec un jeton d'API"
+// @Security BearerAuth
+// @Router /me/tokens [get]
+func GetAPITokens(c *gin.Context) {
+	tokens := []models.APIToken{}
+	err := database.DB.
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", c.GetUint("user_id"), time.Now()).
+		Order("created_at DESC, id DESC").
+		Find(&tokens).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des tokens"})
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+// DeleteAPIToken godoc
+// @Summary Révoquer un jeton d'API
+// @Description Révoque un jeton d'accès personnel : il est refusé immédiatement.
+// @Tags API tokens
+// @Produce json
+// @Param token path int true "ID du jeton"
+// @Success 200 {object} map[string]string "Le token a bien été révoqué"
+// @Failure 403 {object} map[string]string "Action indisponible avec un jeton d'API"
+// @Failure 404 {object} map[string]string "Token non trouvé"
+// @Security BearerAuth
+// @Router /me/tokens/{token} [delete]
+func DeleteAPIToken(c *gin.Context) {
+	result := database.DB.Model(&models.APIToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", paramID(c, "token"), c.GetUint("user_id")).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la révocation du token"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Token non trouvé"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Le token a bien été révoqué"})
+}
@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"net/http"
+
+	"travelmate-api/database"
+	"travelmate-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Nombre maximal d'événements d'audit retournés
+const auditEventsLimit = 200
+
+// UnlockUser godoc
+// @Summary Déverrouiller un compte (admin)
+// @Description Efface les échecs de connexion d'un utilisateur : son compte est déverrouillé immédiatement. Le blocage éventuel de son adresse IP n'est pas levé. Réservé aux administrateurs.
+// @Tags Admin
+// @Produce json
+// @Param id path int true "ID de l'utilisateur"
+// @Success 200 {object} map[string]string "Compte déverrouillé"
+// @Failure 403 {object} map[string]string "Accès réservé aux administrateurs"
+// @Failure 404 {object} map[string]string "Utilisateur non trouvé"
+// @Security BearerAuth
+// @Router /admin/users/{id}/unlock [post]
+func UnlockUser(c *gin.Context) {
+	var user models.User
+	if err := database.DB.First(&user, paramID(c, "id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Utilisateur non trouvé"})
+		return
+	}
+
+	resetLoginFailures(user.Email)
+	actorID := c.GetUint("user_id")
+	recordAuditEvent(&models.AuditEvent{
+		Type:    models.AuditLoginUnlocked,
+		UserID:  &user.ID,
+		ActorID: &actorID,
+		Email:   user.Email,
+		IP:      c.ClientIP(),
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Le compte a bien été déverrouillé"})
+}
+
+// GetAuditEvents godoc
+// @Summary Événements d'audit (admin)
+// @Description Retourne les derniers événements de sécurité (verrouillages et déverrouillages de comptes...), du plus récent au plus ancien. Réservé aux administrateurs.
+// @Tags Admin
+// @Produce json
+// @Param type query string false "Type d'événement (login.locked, login.ip_locked, login.unlocked)"
+// @Param userId query int false "ID de l'utilisateur concerné"
+// @Success 200 {array} models.AuditEvent
+// @Failure 403 {object} map[string]string "Accès réservé aux administrateurs"
+// @Security BearerAuth
+// @Router /admin/audit-events [get]
+func GetAuditEvents(c *gin.Context) {
+	query := database.DB.Order("created_at DESC, id DESC").Limit(auditEventsLimit)
+	if eventType := c.Query("type"); eventType != "" {
+		query = query.Where("type = ?", eventType)
+	}
+	if userID := c.Query("userId"); userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	events := []models.AuditEvent{}
+	if err := query.Find(&events).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des événements"})
+		return
+	}
+	c.JSON(http.StatusOK, events)
+}
@@ -4,15 +4,13 @@ import (
 	"net/http"
 
 	"travelmate-api/database"
+	"travelmate-api/logger"
 	"travelmate-api/models"
-	"travelmate-api/utils"
 
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
 )
 
-var jwtKey = []byte("your_secret_key")
-
 // GetTrips godoc
 // @Summary Création d'un utilisateur
 // @Description Permet à un utilisateur de créer un compte
@@ -62,29 +60,37 @@ func Register(c *gin.Context) {
 		return
 	}
 
-	// Génération du token JWT via ta fonction GenerateJWT
-	tokenString, err := utils.GenerateJWT(user.ID, user.Name , user.IsAdmin)
+	// L'échec de l'envoi ne bloque pas l'inscription : le lien peut être renvoyé
+	if err := sendEmailVerification(&user); err != nil {
+		logger.ErrorLogger.Printf("Erreur lors de l'envoi de l'email de vérification (user_id=%d) : %v", user.ID, err)
+	}
+
+	// Génération du jeton d'accès et du jeton de rafraîchissement
+	tokens, err := login(c, &user)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la génération du token"})
 		return
 	}
 
 	// Réponse
-	c.JSON(http.StatusCreated, gin.H{
-		"message": "Utilisateur créé",
-		"token":   tokenString,
+	c.JSON(http.StatusCreated, models.RegisterResponse{
+		Message:   "Utilisateur créé : un email de vérification a été envoyé",
+		TokenPair: *tokens,
 	})
 }
 
 // GetTrips godoc
 // @Summary Authentification d'un utilisateur
-// @Description Permet à un utilisateur de s'authentifier
+// @Description Permet à un utilisateur de s'authentifier. Après plusieurs échecs pour un même compte ou une même adresse IP, les tentatives sont retardées puis temporairement bloquées.
 // @Tags auth
 // @Accept application/x-www-form-urlencoded
 // @Produce json
 // @Param email formData string true "Email"
 // @Param password formData string true "Mot de passe"
-// @Success 200 {array} models.RegisterResponse
+// @Success 200 {object} models.TokenPair "Connexion établie"
+// @Success 202 {object} models.TwoFactorChallenge "Double authentification requise"
+// @Failure 401 {object} map[string]string "Identifiants invalides"
+// @Failure 429 {object} map[string]string "Trop de tentatives de connexion"
 // @Router /login [post]
 func Login(c *gin.Context) {
 	var input struct {
@@ -97,17 +103,37 @@ func Login(c *gin.Context) {
 		return
 	}
 
+	if !checkLoginThrottle(c, input.Email) {
+		return
+	}
+
+	// Même réponse, et même durée de réponse, que l'email soit inconnu ou le mot de passe incorrect
 	var user models.User
 	if err := database.DB.Where("email = ?", input.Email).First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Utilisateur introuvable"})
+		bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(input.Password))
+		recordLoginFailure(c, input.Email, nil)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Identifiants invalides"})
 		return
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.Password)); err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Mot de passe incorrect"})
+		recordLoginFailure(c, input.Email, &user)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Identifiants invalides"})
 		return
 	}
 
-	token, _ := utils.GenerateJWT(user.ID, user.Name, user.IsAdmin)
-	c.JSON(http.StatusOK, gin.H{"token": token})
+	// Avec la double authentification, la connexion se termine par POST /auth/2fa/verify, qui
+	// oublie les échecs de connexion une fois le second facteur vérifié
+	if user.TwoFactorEnabled {
+		twoFactorChallenge(c, &user)
+		return
+	}
+	resetLoginFailures(input.Email)
+
+	tokens, err := login(c, &user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la génération du token"})
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
 }
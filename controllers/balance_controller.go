@@ -0,0 +1,297 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"travelmate-api/database"
+	"travelmate-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// computeTransfers propose une liste de virements soldant les comptes.
+// Le plus gros débiteur rembourse le plus gros créancier jusqu'à équilibre, ce qui donne
+// au plus n-1 virements pour n participants.
+func computeTransfers(net map[uint]int64) []models.Transfer {
+	type position struct {
+		userID uint
+		amount int64
+	}
+	var creditors, debtors []position
+	for userID, amount := range net {
+		if amount > 0 {
+			creditors = append(creditors, position{userID, amount})
+		} else if amount < 0 {
+			debtors = append(debtors, position{userID, -amount})
+		}
+	}
+	byAmount := func(list []position) func(i, j int) bool {
+		return func(i, j int) bool {
+			if list[i].amount != list[j].amount {
+				return list[i].amount > list[j].amount
+			}
+			return list[i].userID < list[j].userID
+		}
+	}
+	sort.Slice(creditors, byAmount(creditors))
+	sort.Slice(debtors, byAmount(debtors))
+
+	transfers := []models.Transfer{}
+	for i, j := 0, 0; i < len(debtors) && j < len(creditors); {
+		amount := min(debtors[i].amount, creditors[j].amount)
+		transfers = append(transfers, models.Transfer{
+			FromUserID: debtors[i].userID,
+			ToUserID:   creditors[j].userID,
+			Amount:     fromCents(amount),
+		})
+		debtors[i].amount -= amount
+		creditors[j].amount -= amount
+		if debtors[i].amount == 0 {
+			i++
+		}
+		if creditors[j].amount == 0 {
+			j++
+		}
+	}
+	return transfers
+}
+
+// computeBalances calcule, pour chaque devise, ce que chaque participant a payé et doit,
+// en tenant compte des remboursements déjà effectués. Les dépenses enregistrées avant la
+// répartition entre participants, sans parts, sont réparties à parts égales entre eux.
+func computeBalances(expenses []models.Expense, settlements []models.Settlement, participants []uint) ([]models.TripBalances, error) {
+	paid := map[string]map[uint]int64{}
+	owed := map[string]map[uint]int64{}
+	account := func(table map[string]map[uint]int64, currency string, userID uint, cents int64) {
+		if table[currency] == nil {
+			table[currency] = map[uint]int64{}
+		}
+		table[currency][userID] += cents
+	}
+
+	for _, expense := range expenses {
+		if len(expense.Splits) == 0 {
+			expense.SplitType = models.SplitEqual
+			if err := computeSplits(&expense, participants); err != nil {
+				return nil, err
+			}
+		}
+		account(paid, expense.Currency, expense.PayerID, toCents(expense.Amount))
+		account(owed, expense.Currency, expense.PayerID, 0)
+		for _, split := range expense.Splits {
+			account(owed, expense.Currency, split.UserID, toCents(split.Amount))
+		}
+	}
+	// Un remboursement de A vers B augmente ce que A a versé et ce que B a reçu
+	for _, settlement := range settlements {
+		cents := toCents(settlement.Amount)
+		account(paid, settlement.Currency, settlement.FromUserID, cents)
+		account(owed, settlement.Currency, settlement.FromUserID, 0)
+		account(owed, settlement.Currency, settlement.ToUserID, cents)
+	}
+
+	userIDs := []uint{}
+	seen := map[uint]bool{}
+	for _, table := range []map[string]map[uint]int64{paid, owed} {
+		for _, users := range table {
+			for userID := range users {
+				if !seen[userID] {
+					seen[userID] = true
+					userIDs = append(userIDs, userID)
+				}
+			}
+		}
+	}
+	var users []models.User
+	if err := database.DB.Select("id", "name").Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	names := map[uint]string{}
+	for _, user := range users {
+		names[user.ID] = user.Name
+	}
+
+	currencies := make([]string, 0, len(owed))
+	for currency := range owed {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	result := []models.TripBalances{}
+	for _, currency := range currencies {
+		net := map[uint]int64{}
+		balances := []models.UserBalance{}
+		for userID, owedCents := range owed[currency] {
+			paidCents := paid[currency][userID]
+			net[userID] = paidCents - owedCents
+			balances = append(balances, models.UserBalance{
+				UserID: userID,
+				Name:   names[userID],
+				Paid:   fromCents(paidCents),
+				Owed:   fromCents(owedCents),
+				Net:    fromCents(paidCents - owedCents),
+			})
+		}
+		sort.Slice(balances, func(i, j int) bool { return balances[i].UserID < balances[j].UserID })
+
+		result = append(result, models.TripBalances{
+			Currency:  currency,
+			Balances:  balances,
+			Transfers: computeTransfers(net),
+		})
+	}
+	return result, nil
+}
+
+// GetBalances godoc
+// @Summary Équilibre des comptes
+// @Description Calcule, par devise, ce que chaque participant a payé et doit, ainsi qu'une liste réduite de virements permettant de solder les comptes. Une dépense sans répartition est partagée à parts égales entre les participants.
+// @Tags Expenses
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Success 200 {array} models.TripBalances
+// @Failure 404 {object} map[string]string "Voyage non trouvé"
+// @Security BearerAuth
+// @Router /trips/{id}/balances [get]
+func GetBalances(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleViewer)
+	if !ok {
+		return
+	}
+
+	var expenses []models.Expense
+	if err := database.DB.Preload("Splits").Where("trip_id = ?", trip.ID).Find(&expenses).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des dépenses"})
+		return
+	}
+	var settlements []models.Settlement
+	if err := database.DB.Where("trip_id = ?", trip.ID).Find(&settlements).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des remboursements"})
+		return
+	}
+
+	participants, err := tripParticipants(trip)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des participants"})
+		return
+	}
+
+	balances, err := computeBalances(expenses, settlements, participants)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors du calcul des soldes"})
+		return
+	}
+	c.JSON(http.StatusOK, balances)
+}
+
+// GetSettlements godoc
+// @Summary Remboursements d'un voyage
+// @Description Retourne les remboursements enregistrés entre participants
+// @Tags Expenses
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Success 200 {array} models.Settlement
+// @Failure 404 {object} map[string]string "Voyage non trouvé"
+// @Security BearerAuth
+// @Router /trips/{id}/settlements [get]
+func GetSettlements(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleViewer)
+	if !ok {
+		return
+	}
+
+	settlements := []models.Settlement{}
+	if err := database.DB.Where("trip_id = ?", trip.ID).Order("created_at, id").Find(&settlements).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des remboursements"})
+		return
+	}
+	c.JSON(http.StatusOK, settlements)
+}
+
+// validateSettlement vérifie les participants et la date d'un remboursement
+func validateSettlement(trip *models.Trip, settlement *models.Settlement) error {
+	settlement.Currency = strings.ToUpper(strings.TrimSpace(settlement.Currency))
+	settlement.Amount = roundAmount(settlement.Amount)
+	if settlement.FromUserID == settlement.ToUserID {
+		return errors.New("Un participant ne peut pas se rembourser lui-même")
+	}
+	if settlement.Date != "" {
+		if _, err := time.Parse(dateLayout, settlement.Date); err != nil {
+			return errors.New("La date doit être au format AAAA-MM-JJ")
+		}
+	}
+	return checkParticipants(trip, []uint{settlement.FromUserID, settlement.ToUserID})
+}
+
+// CreateSettlement godoc
+// @Summary Enregistrer un remboursement
+// @Description Enregistre un paiement d'un participant à un autre, qui vient réduire leurs soldes
+// @Tags Expenses
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Param settlement body models.Settlement true "Remboursement"
+// @Success 201 {object} models.Settlement
+// @Failure 400 {object} map[string]string "Format invalide"
+// @Failure 404 {object} map[string]string "Voyage non trouvé"
+// @Security BearerAuth
+// @Router /trips/{id}/settlements [post]
+func CreateSettlement(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleEditor)
+	if !ok {
+		return
+	}
+
+	var settlement models.Settlement
+	if err := c.ShouldBindJSON(&settlement); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Format invalide"})
+		return
+	}
+	settlement.ID = 0
+	settlement.TripID = trip.ID
+
+	if err := validateSettlement(trip, &settlement); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := database.DB.Create(&settlement).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de l'enregistrement du remboursement"})
+		return
+	}
+	c.JSON(http.StatusCreated, settlement)
+}
+
+// DeleteSettlement godoc
+// @Summary Annuler un remboursement
+// @Description Supprime un remboursement enregistré par erreur
+// @Tags Expenses
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Param settlement path int true "ID du remboursement"
+// @Success 200 {object} map[string]string "Le remboursement a bien été supprimé"
+// @Failure 404 {object} map[string]string "Remboursement non trouvé"
+// @Security BearerAuth
+// @Router /trips/{id}/settlements/{settlement} [delete]
+func DeleteSettlement(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleEditor)
+	if !ok {
+		return
+	}
+
+	var settlement models.Settlement
+	if err := database.DB.Where("trip_id = ?", trip.ID).First(&settlement, paramID(c, "settlement")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Remboursement non trouvé"})
+		return
+	}
+
+	if err := database.DB.Delete(&settlement).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la suppression"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Le remboursement a bien été supprimé"})
+}
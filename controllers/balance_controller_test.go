@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	"travelmate-api/models"
+)
+
+func TestComputeTransfers(t *testing.T) {
+	tests := []struct {
+		name string
+		net  map[uint]int64
+		want []models.Transfer
+	}{
+		{
+			name: "settled",
+			net:  map[uint]int64{1: 0, 2: 0},
+			want: []models.Transfer{},
+		},
+		{
+			name: "one debtor",
+			net:  map[uint]int64{1: 1500, 2: -1500},
+			want: []models.Transfer{{FromUserID: 2, ToUserID: 1, Amount: 15}},
+		},
+		{
+			name: "largest debtor pays largest creditor first",
+			net:  map[uint]int64{1: 6000, 2: 1000, 3: -4500, 4: -2500},
+			want: []models.Transfer{
+				{FromUserID: 3, ToUserID: 1, Amount: 45},
+				{FromUserID: 4, ToUserID: 1, Amount: 15},
+				{FromUserID: 4, ToUserID: 2, Amount: 10},
+			},
+		},
+		{
+			name: "ties broken by user id",
+			net:  map[uint]int64{3: 1000, 1: 1000, 4: -1000, 2: -1000},
+			want: []models.Transfer{
+				{FromUserID: 2, ToUserID: 1, Amount: 10},
+				{FromUserID: 4, ToUserID: 3, Amount: 10},
+			},
+		},
+		{
+			name: "cents",
+			net:  map[uint]int64{1: 667, 2: -333, 3: -334},
+			want: []models.Transfer{
+				{FromUserID: 3, ToUserID: 1, Amount: 3.34},
+				{FromUserID: 2, ToUserID: 1, Amount: 3.33},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := computeTransfers(tt.net)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("computeTransfers() = %+v, want %+v", got, tt.want)
+			}
+			if len(got) > 0 && len(got) >= len(tt.net) {
+				t.Errorf("%d transfers for %d participants, want at most n-1", len(got), len(tt.net))
+			}
+		})
+	}
+}
+
+func TestComputeBalancesSplitsExpensesWithoutShares(t *testing.T) {
+	setupTestDB(t)
+	expenses := []models.Expense{
+		{Amount: 30, Currency: "EUR", PayerID: 1},
+		{Amount: 10, Currency: "EUR", PayerID: 2, Splits: []models.ExpenseSplit{{UserID: 1, Amount: 10}}},
+	}
+	settlements := []models.Settlement{{FromUserID: 3, ToUserID: 1, Amount: 5, Currency: "EUR"}}
+
+	got, err := computeBalances(expenses, settlements, []uint{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []models.TripBalances{{
+		Currency: "EUR",
+		Balances: []models.UserBalance{
+			{UserID: 1, Paid: 30, Owed: 25, Net: 5},
+			{UserID: 2, Paid: 10, Owed: 10, Net: 0},
+			{UserID: 3, Paid: 5, Owed: 10, Net: -5},
+		},
+		Transfers: []models.Transfer{{FromUserID: 3, ToUserID: 1, Amount: 5}},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("computeBalances() = %+v, want %+v", got, want)
+	}
+}
+
+func TestComputeBalancesKeepsSettlementSenders(t *testing.T) {
+	setupTestDB(t)
+	expenses := []models.Expense{
+		{Amount: 30, Currency: "EUR", PayerID: 1, SplitType: models.SplitExact, Splits: []models.ExpenseSplit{{UserID: 1, Amount: 10}, {UserID: 2, Amount: 20}}},
+	}
+	settlements := []models.Settlement{
+		{FromUserID: 3, ToUserID: 1, Amount: 5, Currency: "EUR"},
+		// Devise sans dépense
+		{FromUserID: 3, ToUserID: 1, Amount: 7, Currency: "USD"},
+	}
+
+	got, err := computeBalances(expenses, settlements, []uint{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantNets := map[string]map[uint]float64{
+		"EUR": {1: 15, 2: -20, 3: 5},
+		"USD": {1: -7, 3: 7},
+	}
+	if len(got) != len(wantNets) {
+		t.Fatalf("computeBalances() = %+v, want currencies %v", got, wantNets)
+	}
+	for _, currency := range got {
+		nets := map[uint]float64{}
+		var sum int64
+		for _, balance := range currency.Balances {
+			nets[balance.UserID] = balance.Net
+			sum += toCents(balance.Net)
+		}
+		if !reflect.DeepEqual(nets, wantNets[currency.Currency]) {
+			t.Errorf("%s nets = %v, want %v", currency.Currency, nets, wantNets[currency.Currency])
+		}
+		if sum != 0 {
+			t.Errorf("%s nets sum to %d cents, want 0", currency.Currency, sum)
+		}
+	}
+}
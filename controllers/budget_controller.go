@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	"travelmate-api/database"
+	"travelmate-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// buildBudgetSummary compare les dépenses du voyage au budget prévu.
+// Seules les dépenses dans la devise du budget sont comptabilisées, les autres sont totalisées à part.
+func buildBudgetSummary(budget *models.Budget, expenses []models.Expense) models.BudgetSummary {
+	summary := models.BudgetSummary{
+		Currency:   budget.Currency,
+		Categories: []models.BudgetCategorySummary{},
+	}
+
+	planned := map[string]float64{}
+	for _, category := range budget.Categories {
+		planned[category.Category] += category.Planned
+		summary.Planned += category.Planned
+	}
+
+	spent := map[string]float64{}
+	for _, expense := range expenses {
+		if expense.Currency != budget.Currency {
+			if summary.OtherCurrencies == nil {
+				summary.OtherCurrencies = map[string]float64{}
+			}
+			summary.OtherCurrencies[expense.Currency] = roundAmount(summary.OtherCurrencies[expense.Currency] + expense.Amount)
+			continue
+		}
+		spent[expense.Category] += expense.Amount
+		summary.Spent += expense.Amount
+	}
+
+	// Les catégories sans plafond apparaissent avec un budget prévu nul
+	names := make([]string, 0, len(planned))
+	for name := range planned {
+		names = append(names, name)
+	}
+	for name := range spent {
+		if _, ok := planned[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		category := models.BudgetCategorySummary{
+			Category:  name,
+			Planned:   roundAmount(planned[name]),
+			Spent:     roundAmount(spent[name]),
+			Remaining: roundAmount(planned[name] - spent[name]),
+		}
+		category.Overrun = category.Spent > category.Planned
+		summary.Categories = append(summary.Categories, category)
+	}
+
+	summary.Planned = roundAmount(summary.Planned)
+	summary.Spent = roundAmount(summary.Spent)
+	summary.Remaining = roundAmount(summary.Planned - summary.Spent)
+	summary.Overrun = summary.Spent > summary.Planned
+	return summary
+}
+
+// GetBudget godoc
+// @Summary Bilan du budget
+// @Description Compare, par catégorie, les dépenses du voyage au budget prévu et signale les dépassements
+// @Tags Expenses
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Success 200 {object} models.BudgetSummary
+// @Failure 404 {object} map[string]string "Aucun budget défini pour ce voyage"
+// @Security BearerAuth
+// @Router /trips/{id}/budget [get]
+func GetBudget(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleViewer)
+	if !ok {
+		return
+	}
+
+	var budget models.Budget
+	if err := database.DB.Preload("Categories").Where("trip_id = ?", trip.ID).First(&budget).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Aucun budget défini pour ce voyage"})
+		return
+	}
+
+	var expenses []models.Expense
+	if err := database.DB.Where("trip_id = ?", trip.ID).Find(&expenses).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des dépenses"})
+		return
+	}
+
+	c.JSON(http.StatusOK, buildBudgetSummary(&budget, expenses))
+}
+
+// SetBudget godoc
+// @Summary Définir le budget
+// @Description Crée ou remplace le budget du voyage et ses plafonds par catégorie
+// @Tags Expenses
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Param budget body models.Budget true "Budget"
+// @Success 200 {object} models.Budget
+// @Failure 400 {object} map[string]string "Format invalide"
+// @Failure 404 {object} map[string]string "Voyage non trouvé"
+// @Security BearerAuth
+// @Router /trips/{id}/budget [put]
+func SetBudget(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleEditor)
+	if !ok {
+		return
+	}
+
+	var input models.Budget
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Format invalide"})
+		return
+	}
+
+	categories := make([]models.BudgetCategory, 0, len(input.Categories))
+	seen := map[string]bool{}
+	for _, category := range input.Categories {
+		name := strings.ToLower(strings.TrimSpace(category.Category))
+		if name == "" || category.Planned < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Chaque catégorie doit avoir un nom et un montant positif"})
+			return
+		}
+		if seen[name] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "La catégorie " + name + " est définie plusieurs fois"})
+			return
+		}
+		seen[name] = true
+		categories = append(categories, models.BudgetCategory{Category: name, Planned: roundAmount(category.Planned)})
+	}
+
+	var budget models.Budget
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("trip_id = ?", trip.ID).First(&budget).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if budget.ID != 0 {
+			if err := tx.Where("budget_id = ?", budget.ID).Delete(&models.BudgetCategory{}).Error; err != nil {
+				return err
+			}
+		}
+
+		budget.TripID = trip.ID
+		budget.Currency = strings.ToUpper(input.Currency)
+		budget.Categories = categories
+		return tx.Save(&budget).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de l'enregistrement du budget"})
+		return
+	}
+	c.JSON(http.StatusOK, budget)
+}
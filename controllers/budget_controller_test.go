@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	"travelmate-api/models"
+)
+
+func TestBuildBudgetSummary(t *testing.T) {
+	budget := &models.Budget{
+		Currency: "EUR",
+		Categories: []models.BudgetCategory{
+			{Category: "restaurant", Planned: 300},
+			{Category: "transport", Planned: 100},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		expenses []models.Expense
+		want     models.BudgetSummary
+	}{
+		{
+			name: "no expenses",
+			want: models.BudgetSummary{
+				Currency: "EUR", Planned: 400, Remaining: 400,
+				Categories: []models.BudgetCategorySummary{
+					{Category: "restaurant", Planned: 300, Remaining: 300},
+					{Category: "transport", Planned: 100, Remaining: 100},
+				},
+			},
+		},
+		{
+			name: "overrun and category without a cap",
+			expenses: []models.Expense{
+				{Amount: 0.1, Currency: "EUR", Category: "transport"},
+				{Amount: 100.2, Currency: "EUR", Category: "transport"},
+				{Amount: 42.5, Currency: "EUR", Category: "activity"},
+				{Amount: 120, Currency: "EUR", Category: "restaurant"},
+			},
+			want: models.BudgetSummary{
+				Currency: "EUR", Planned: 400, Spent: 262.8, Remaining: 137.2,
+				Categories: []models.BudgetCategorySummary{
+					{Category: "activity", Spent: 42.5, Remaining: -42.5, Overrun: true},
+					{Category: "restaurant", Planned: 300, Spent: 120, Remaining: 180},
+					{Category: "transport", Planned: 100, Spent: 100.3, Remaining: -0.3, Overrun: true},
+				},
+			},
+		},
+		{
+			name: "other currencies are totalled apart",
+			expenses: []models.Expense{
+				{Amount: 50, Currency: "EUR", Category: "restaurant"},
+				{Amount: 10.1, Currency: "USD", Category: "restaurant"},
+				{Amount: 0.2, Currency: "USD", Category: "transport"},
+			},
+			want: models.BudgetSummary{
+				Currency: "EUR", Planned: 400, Spent: 50, Remaining: 350,
+				Categories: []models.BudgetCategorySummary{
+					{Category: "restaurant", Planned: 300, Spent: 50, Remaining: 250},
+					{Category: "transport", Planned: 100, Remaining: 100},
+				},
+				OtherCurrencies: map[string]float64{"USD": 10.3},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildBudgetSummary(budget, tt.expenses)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildBudgetSummary() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
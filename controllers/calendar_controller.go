@@ -0,0 +1,239 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"travelmate-api/database"
+	"travelmate-api/ics"
+	"travelmate-api/models"
+	"travelmate-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	calendarProdID = "-//TravelMate//TravelMate API//FR"
+	// Domaine des identifiants (UID) des événements exportés
+	calendarUIDDomain = "travelmate-api"
+	// Durée par défaut d'une étape dont seule l'heure de début est connue
+	defaultStopDuration = time.Hour
+)
+
+// tripEvents convertit un voyage et son programme en événements iCalendar : le voyage
+// entier sur des journées complètes, puis chaque étape (avec ses horaires s'ils sont connus)
+// et chaque journée sans étape mais avec un titre
+func tripEvents(trip *models.Trip, days []models.ItineraryDay) ([]ics.Event, error) {
+	start, err := time.Parse(dateLayout, trip.StartDate)
+	if err != nil {
+		return nil, errors.New("Le voyage n'a pas de date de début valide")
+	}
+	end, err := time.Parse(dateLayout, trip.EndDate)
+	if err != nil || end.Before(start) {
+		end = start
+	}
+
+	tripEvent := ics.Event{
+		UID:         fmt.Sprintf("trip-%d@%s", trip.ID, calendarUIDDomain),
+		Summary:     trip.Title,
+		Description: trip.Description,
+		Location:    trip.Location,
+		URL:         fmt.Sprintf("%s/trips/%d", appURL(), trip.ID),
+		Start:       start,
+		End:         end,
+		AllDay:      true,
+	}
+	if hasCoordinates(trip.Latitude, trip.Longitude) {
+		tripEvent.HasGeo, tripEvent.Latitude, tripEvent.Longitude = true, trip.Latitude, trip.Longitude
+	}
+	events := []ics.Event{tripEvent}
+
+	for _, day := range days {
+		date, err := time.Parse(dateLayout, day.Date)
+		if err != nil {
+			continue
+		}
+		if len(day.Stops) == 0 && day.Title != "" {
+			events = append(events, ics.Event{
+				UID:         fmt.Sprintf("day-%d@%s", day.ID, calendarUIDDomain),
+				Summary:     day.Title + " (" + trip.Title + ")",
+				Description: day.Notes,
+				Start:       date,
+				End:         date,
+				AllDay:      true,
+			})
+		}
+		for _, stop := range day.Stops {
+			events = append(events, stopEvent(trip, date, &stop))
+		}
+	}
+	return events, nil
+}
+
+// stopEvent convertit une étape en événement, sur la journée entière si elle n'a pas d'horaire
+func stopEvent(trip *models.Trip, date time.Time, stop *models.Stop) ics.Event {
+	event := ics.Event{
+		UID:         fmt.Sprintf("stop-%d@%s", stop.ID, calendarUIDDomain),
+		Summary:     stop.Name + " (" + trip.Title + ")",
+		Description: stop.Notes,
+		Start:       date,
+		End:         date,
+		AllDay:      true,
+	}
+	if hasCoordinates(stop.Latitude, stop.Longitude) {
+		event.HasGeo, event.Latitude, event.Longitude = true, stop.Latitude, stop.Longitude
+	}
+
+	// Les horaires sont exprimés à l'heure locale du lieu, sans fuseau horaire
+	if startTime, err := time.Parse(timeLayout, stop.StartTime); err == nil {
+		event.AllDay = false
+		event.Start = time.Date(date.Year(), date.Month(), date.Day(), startTime.Hour(), startTime.Minute(), 0, 0, time.Local)
+		event.End = event.Start.Add(defaultStopDuration)
+		if endTime, err := time.Parse(timeLayout, stop.EndTime); err == nil {
+			event.End = time.Date(date.Year(), date.Month(), date.Day(), endTime.Hour(), endTime.Minute(), 0, 0, time.Local)
+		}
+	}
+	return event
+}
+
+// writeCalendar envoie le calendrier en réponse, en pièce jointe si filename est renseigné
+func writeCalendar(c *gin.Context, cal *ics.Calendar, filename string) {
+	if filename != "" {
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	}
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(cal.String()))
+}
+
+// ExportTripCalendar godoc
+// @Summary Exporter un voyage au format iCalendar
+// @Description Retourne un fichier .ics (RFC 5545) contenant le voyage sur des journées entières, ainsi que les étapes et journées de son programme.
+// @Tags Calendar
+// @Produce text/calendar
+// @Param id path int true "ID du voyage"
+// @Success 200 {string} string "Calendrier iCalendar"
+// @Failure 403 {object} map[string]string "Accès refusé"
+// @Failure 404 {object} map[string]string "Voyage non trouvé"
+// @Failure 422 {object} map[string]string "Le voyage n'a pas de date de début valide"
+// @Security BearerAuth
+// @Router /trips/{id}/calendar.ics [get]
+func ExportTripCalendar(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleViewer)
+	if !ok {
+		return
+	}
+	days, err := loadItinerary(trip.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération du programme"})
+		return
+	}
+
+	events, err := tripEvents(trip, days)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	cal := &ics.Calendar{ProdID: calendarProdID, Name: trip.Title, Events: events}
+	writeCalendar(c, cal, fmt.Sprintf("trip-%d.ics", trip.ID))
+}
+
+// CreateCalendarFeed godoc
+// @Summary Créer le flux iCalendar personnel
+// @Description Crée l'adresse secrète du flux iCalendar de l'utilisateur, à laquelle une application de calendrier peut s'abonner sans authentification. Un nouvel appel remplace l'adresse précédente, qui cesse de fonctionner. Le jeton n'est retourné qu'une seule fois.
+// @Tags Calendar
+// @Produce json
+// @Success 201 {object} models.CalendarFeed
+// @Failure 500 {object} map[string]string "Erreur lors de la création du flux"
+// @Security BearerAuth
+// @Router /me/calendar-feed [post]
+func CreateCalendarFeed(c *gin.Context) {
+	token, err := utils.GenerateRandomToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la création du flux"})
+		return
+	}
+
+	feed := models.CalendarFeed{UserID: c.GetUint("user_id"), TokenHash: utils.HashToken(token)}
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", feed.UserID).Delete(&models.CalendarFeed{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&feed).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la création du flux"})
+		return
+	}
+
+	feed.Token = token
+	feed.URL = requestBaseURL(c) + "/calendar/" + token + ".ics"
+	c.JSON(http.StatusCreated, feed)
+}
+
+// DeleteCalendarFeed godoc
+// @Summary Supprimer le flux iCalendar personnel
+// @Description Désactive l'adresse du flux iCalendar de l'utilisateur.
+// @Tags Calendar
+// @Produce json
+// @Success 200 {object} map[string]string "Le flux a bien été supprimé"
+// @Failure 404 {object} map[string]string "Aucun flux actif"
+// @Security BearerAuth
+// @Router /me/calendar-feed [delete]
+func DeleteCalendarFeed(c *gin.Context) {
+	result := database.DB.Where("user_id = ?", c.GetUint("user_id")).Delete(&models.CalendarFeed{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la suppression"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Aucun flux actif"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Le flux a bien été supprimé"})
+}
+
+// GetCalendarFeed godoc
+// @Summary Flux iCalendar d'un utilisateur
+// @Description Retourne, sans authentification, les voyages dont l'utilisateur est propriétaire ou membre au format iCalendar. L'adresse est celle retournée par POST /me/calendar-feed.
+// @Tags Calendar
+// @Produce text/calendar
+// @Param token path string true "Jeton du flux, suivi de .ics"
+// @Success 200 {string} string "Calendrier iCalendar"
+// @Failure 404 {object} map[string]string "Flux introuvable"
+// @Router /calendar/{token} [get]
+func GetCalendarFeed(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	var feed models.CalendarFeed
+	if err := database.DB.Where("token_hash = ?", utils.HashToken(token)).First(&feed).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Flux introuvable"})
+		return
+	}
+
+	var trips []models.Trip
+	if err := tripsVisibleTo(database.DB.Model(&models.Trip{}), feed.UserID).Order("start_date, id").Find(&trips).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des voyages"})
+		return
+	}
+
+	cal := &ics.Calendar{ProdID: calendarProdID, Name: "TravelMate"}
+	for i := range trips {
+		days, err := loadItinerary(trips[i].ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération du programme"})
+			return
+		}
+		// Les voyages sans dates valides n'apparaissent pas dans le flux
+		events, err := tripEvents(&trips[i], days)
+		if err != nil {
+			continue
+		}
+		cal.Events = append(cal.Events, events...)
+	}
+
+	writeCalendar(c, cal, "")
+}
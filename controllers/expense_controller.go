@@ -0,0 +1,401 @@
+package controllers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"travelmate-api/database"
+	"travelmate-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// roundAmount arrondit un montant au centime
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// normalizeExpense harmonise la devise et la catégorie puis valide la date
+func normalizeExpense(expense *models.Expense) error {
+	expense.Currency = strings.ToUpper(strings.TrimSpace(expense.Currency))
+	expense.Category = strings.ToLower(strings.TrimSpace(expense.Category))
+	expense.Amount = roundAmount(expense.Amount)
+	if expense.Category == "" {
+		return errors.New("La catégorie est requise")
+	}
+	if expense.Date != "" {
+		if _, err := time.Parse(dateLayout, expense.Date); err != nil {
+			return errors.New("La date doit être au format AAAA-MM-JJ")
+		}
+	}
+	return nil
+}
+
+// toCents convertit un montant en centimes pour les calculs de répartition
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// fromCents convertit des centimes en montant
+func fromCents(cents int64) float64 {
+	return float64(cents) / 100
+}
+
+// tripParticipants retourne les participants d'un voyage (propriétaire et membres),
+// entre lesquels une dépense est répartie par défaut
+func tripParticipants(trip *models.Trip) ([]uint, error) {
+	var memberIDs []uint
+	if err := database.DB.Model(&models.TripMember{}).Where("trip_id = ?", trip.ID).Order("id").Pluck("user_id", &memberIDs).Error; err != nil {
+		return nil, err
+	}
+
+	participants := []uint{}
+	if trip.UserID != 0 {
+		participants = append(participants, trip.UserID)
+	}
+	for _, id := range memberIDs {
+		if id != trip.UserID {
+			participants = append(participants, id)
+		}
+	}
+	return participants, nil
+}
+
+// checkParticipants vérifie que les utilisateurs donnés participent au voyage
+func checkParticipants(trip *models.Trip, userIDs []uint) error {
+	participants, err := tripParticipants(trip)
+	if err != nil {
+		return err
+	}
+	allowed := map[uint]bool{}
+	for _, id := range participants {
+		allowed[id] = true
+	}
+	for _, id := range userIDs {
+		if !allowed[id] {
+			return errors.New("Un des utilisateurs ne participe pas à ce voyage")
+		}
+	}
+	return nil
+}
+
+// computeSplits calcule la part due par chaque participant selon le mode de répartition.
+// Les centimes restants sont attribués un à un aux premiers participants afin que la somme des parts
+// soit toujours égale au montant de la dépense.
+func computeSplits(expense *models.Expense, participants []uint) error {
+	if expense.SplitType == "" {
+		expense.SplitType = models.SplitEqual
+	}
+	if len(expense.Splits) == 0 && expense.SplitType == models.SplitEqual {
+		for _, userID := range participants {
+			expense.Splits = append(expense.Splits, models.ExpenseSplit{UserID: userID})
+		}
+	}
+	if len(expense.Splits) == 0 {
+		if expense.PayerID == 0 {
+			return errors.New("Aucun participant pour la répartition")
+		}
+		expense.Splits = []models.ExpenseSplit{{UserID: expense.PayerID}}
+	}
+
+	seen := map[uint]bool{}
+	for i := range expense.Splits {
+		split := &expense.Splits[i]
+		split.ID = 0
+		if split.UserID == 0 || seen[split.UserID] {
+			return errors.New("Chaque participant doit apparaître une seule fois dans la répartition")
+		}
+		seen[split.UserID] = true
+	}
+
+	total := toCents(expense.Amount)
+	parts := make([]int64, len(expense.Splits))
+
+	switch expense.SplitType {
+	case models.SplitEqual:
+		n := int64(len(parts))
+		for i := range parts {
+			parts[i] = total / n
+			if int64(i) < total%n {
+				parts[i]++
+			}
+		}
+
+	case models.SplitShares:
+		var shares float64
+		for _, split := range expense.Splits {
+			if split.Shares <= 0 {
+				return errors.New("Chaque participant doit avoir un nombre de parts positif")
+			}
+			shares += split.Shares
+		}
+		// Méthode du plus fort reste
+		remainders := make([]float64, len(parts))
+		var assigned int64
+		for i, split := range expense.Splits {
+			exact := float64(total) * split.Shares / shares
+			parts[i] = int64(math.Floor(exact))
+			remainders[i] = exact - float64(parts[i])
+			assigned += parts[i]
+		}
+		order := make([]int, len(parts))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool { return remainders[order[a]] > remainders[order[b]] })
+		for i := 0; assigned < total; i++ {
+			parts[order[i%len(order)]]++
+			assigned++
+		}
+
+	case models.SplitExact:
+		var sum int64
+		for i, split := range expense.Splits {
+			if split.Amount < 0 {
+				return errors.New("Les montants de la répartition doivent être positifs")
+			}
+			parts[i] = toCents(split.Amount)
+			sum += parts[i]
+		}
+		if sum != total {
+			return errors.New("La somme des montants de la répartition doit être égale au montant de la dépense")
+		}
+
+	default:
+		return errors.New("Mode de répartition invalide (equal, shares ou exact)")
+	}
+
+	for i := range expense.Splits {
+		expense.Splits[i].Amount = fromCents(parts[i])
+		if expense.SplitType != models.SplitShares {
+			expense.Splits[i].Shares = 0
+		}
+	}
+	return nil
+}
+
+// prepareSplits valide le payeur et les participants puis calcule la répartition de la dépense
+func prepareSplits(trip *models.Trip, expense *models.Expense) error {
+	participants, err := tripParticipants(trip)
+	if err != nil {
+		return err
+	}
+	if err := computeSplits(expense, participants); err != nil {
+		return err
+	}
+
+	userIDs := []uint{expense.PayerID}
+	for _, split := range expense.Splits {
+		userIDs = append(userIDs, split.UserID)
+	}
+	return checkParticipants(trip, userIDs)
+}
+
+// loadExpense récupère la dépense :expense appartenant au voyage donné
+func loadExpense(c *gin.Context, trip *models.Trip) (*models.Expense, bool) {
+	var expense models.Expense
+	if err := database.DB.Preload("Splits").Where("trip_id = ?", trip.ID).First(&expense, paramID(c, "expense")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Dépense non trouvée"})
+		return nil, false
+	}
+	return &expense, true
+}
+
+// GetExpenses godoc
+// @Summary Dépenses d'un voyage
+// @Description Retourne les dépenses d'un voyage, éventuellement filtrées par catégorie
+// @Tags Expenses
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Param category query string false "Catégorie"
+// @Success 200 {array} models.Expense
+// @Failure 404 {object} map[string]string "Voyage non trouvé"
+// @Security BearerAuth
+// @Router /trips/{id}/expenses [get]
+func GetExpenses(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleViewer)
+	if !ok {
+		return
+	}
+
+	query := database.DB.Where("trip_id = ?", trip.ID)
+	if category := c.Query("category"); category != "" {
+		query = query.Where("category = ?", strings.ToLower(category))
+	}
+
+	expenses := []models.Expense{}
+	if err := query.Preload("Splits").Order("date, id").Find(&expenses).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des dépenses"})
+		return
+	}
+	c.JSON(http.StatusOK, expenses)
+}
+
+// CreateExpense godoc
+// @Summary Ajouter une dépense
+// @Description Ajoute une dépense au voyage. Si payerId est absent, l'utilisateur connecté est considéré comme le payeur.
+// @Description La dépense est répartie entre les participants selon splitType : equal (parts égales, par défaut entre tous les participants), shares (au prorata des parts) ou exact (montants fixés).
+// @Tags Expenses
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Param expense body models.Expense true "Dépense"
+// @Success 201 {object} models.Expense
+// @Failure 400 {object} map[string]string "Format invalide"
+// @Failure 404 {object} map[string]string "Voyage non trouvé"
+// @Security BearerAuth
+// @Router /trips/{id}/expenses [post]
+func CreateExpense(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleEditor)
+	if !ok {
+		return
+	}
+
+	var expense models.Expense
+	if err := c.ShouldBindJSON(&expense); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Format invalide"})
+		return
+	}
+	expense.ID = 0
+	expense.TripID = trip.ID
+	if expense.PayerID == 0 {
+		expense.PayerID = c.GetUint("user_id")
+	}
+
+	if err := normalizeExpense(&expense); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := prepareSplits(trip, &expense); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := database.DB.Create(&expense).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la création de la dépense"})
+		return
+	}
+	c.JSON(http.StatusCreated, expense)
+}
+
+// GetExpense godoc
+// @Summary Récupérer une dépense
+// @Description Retourne une dépense du voyage
+// @Tags Expenses
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Param expense path int true "ID de la dépense"
+// @Success 200 {object} models.Expense
+// @Failure 404 {object} map[string]string "Dépense non trouvée"
+// @Security BearerAuth
+// @Router /trips/{id}/expenses/{expense} [get]
+func GetExpense(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleViewer)
+	if !ok {
+		return
+	}
+	expense, ok := loadExpense(c, trip)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, expense)
+}
+
+// UpdateExpense godoc
+// @Summary Mettre à jour une dépense
+// @Description Met à jour une dépense du voyage. Si splits est absent, la répartition existante est recalculée sur le nouveau montant.
+// @Tags Expenses
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Param expense path int true "ID de la dépense"
+// @Param data body models.Expense true "Nouvelles données de la dépense"
+// @Success 200 {object} models.Expense
+// @Failure 400 {object} map[string]string "Format invalide"
+// @Failure 404 {object} map[string]string "Dépense non trouvée"
+// @Security BearerAuth
+// @Router /trips/{id}/expenses/{expense} [put]
+func UpdateExpense(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleEditor)
+	if !ok {
+		return
+	}
+	expense, ok := loadExpense(c, trip)
+	if !ok {
+		return
+	}
+
+	// Sans nouvelle répartition, l'ancienne est recalculée sur le nouveau montant
+	id := expense.ID
+	previousSplits := expense.Splits
+	expense.Splits = nil
+	if err := c.ShouldBindJSON(expense); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Format invalide"})
+		return
+	}
+	expense.ID = id
+	expense.TripID = trip.ID
+	if expense.Splits == nil {
+		expense.Splits = previousSplits
+	}
+
+	if err := normalizeExpense(expense); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := prepareSplits(trip, expense); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expense_id = ?", expense.ID).Delete(&models.ExpenseSplit{}).Error; err != nil {
+			return err
+		}
+		return tx.Save(expense).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la mise à jour de la dépense"})
+		return
+	}
+	c.JSON(http.StatusOK, expense)
+}
+
+// DeleteExpense godoc
+// @Summary Supprimer une dépense
+// @Description Supprime une dépense du voyage
+// @Tags Expenses
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Param expense path int true "ID de la dépense"
+// @Success 200 {object} map[string]string "La dépense a bien été supprimée"
+// @Failure 404 {object} map[string]string "Dépense non trouvée"
+// @Security BearerAuth
+// @Router /trips/{id}/expenses/{expense} [delete]
+func DeleteExpense(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleEditor)
+	if !ok {
+		return
+	}
+	expense, ok := loadExpense(c, trip)
+	if !ok {
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expense_id = ?", expense.ID).Delete(&models.ExpenseSplit{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(expense).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la suppression"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "La dépense a bien été supprimée"})
+}
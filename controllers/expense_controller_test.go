@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	"travelmate-api/models"
+)
+
+func TestComputeSplits(t *testing.T) {
+	tests := []struct {
+		name         string
+		expense      models.Expense
+		participants []uint
+		wantUsers    []uint
+		wantAmounts  []float64
+		wantErr      bool
+	}{
+		{
+			name:         "equal between participants by default",
+			expense:      models.Expense{Amount: 10, PayerID: 1},
+			participants: []uint{1, 2, 3},
+			wantUsers:    []uint{1, 2, 3},
+			wantAmounts:  []float64{3.34, 3.33, 3.33},
+		},
+		{
+			name:        "equal between given participants",
+			expense:     models.Expense{Amount: 0.05, SplitType: models.SplitEqual, Splits: []models.ExpenseSplit{{UserID: 2}, {UserID: 3}}},
+			wantUsers:   []uint{2, 3},
+			wantAmounts: []float64{0.03, 0.02},
+		},
+		{
+			name:        "payer alone without participants",
+			expense:     models.Expense{Amount: 12.5, PayerID: 4},
+			wantUsers:   []uint{4},
+			wantAmounts: []float64{12.5},
+		},
+		{
+			name:    "no participant and no payer",
+			expense: models.Expense{Amount: 12.5},
+			wantErr: true,
+		},
+		{
+			name:        "shares with largest remainder",
+			expense:     models.Expense{Amount: 10, SplitType: models.SplitShares, Splits: []models.ExpenseSplit{{UserID: 1, Shares: 1}, {UserID: 2, Shares: 2}}},
+			wantUsers:   []uint{1, 2},
+			wantAmounts: []float64{3.33, 6.67},
+		},
+		{
+			name:        "shares of a single cent",
+			expense:     models.Expense{Amount: 0.01, SplitType: models.SplitShares, Splits: []models.ExpenseSplit{{UserID: 1, Shares: 1}, {UserID: 2, Shares: 1}, {UserID: 3, Shares: 1}}},
+			wantUsers:   []uint{1, 2, 3},
+			wantAmounts: []float64{0.01, 0, 0},
+		},
+		{
+			name:        "fractional shares",
+			expense:     models.Expense{Amount: 100, SplitType: models.SplitShares, Splits: []models.ExpenseSplit{{UserID: 1, Shares: 0.5}, {UserID: 2, Shares: 1.5}, {UserID: 3, Shares: 1}}},
+			wantUsers:   []uint{1, 2, 3},
+			wantAmounts: []float64{16.67, 50, 33.33},
+		},
+		{
+			name:    "shares must be positive",
+			expense: models.Expense{Amount: 10, SplitType: models.SplitShares, Splits: []models.ExpenseSplit{{UserID: 1, Shares: 1}, {UserID: 2}}},
+			wantErr: true,
+		},
+		{
+			name:        "exact amounts",
+			expense:     models.Expense{Amount: 20, SplitType: models.SplitExact, Splits: []models.ExpenseSplit{{UserID: 1, Amount: 12.35}, {UserID: 2, Amount: 7.65}}},
+			wantUsers:   []uint{1, 2},
+			wantAmounts: []float64{12.35, 7.65},
+		},
+		{
+			name:    "exact amounts not matching the total",
+			expense: models.Expense{Amount: 20, SplitType: models.SplitExact, Splits: []models.ExpenseSplit{{UserID: 1, Amount: 12.35}, {UserID: 2, Amount: 7.64}}},
+			wantErr: true,
+		},
+		{
+			name:    "negative exact amount",
+			expense: models.Expense{Amount: 5, SplitType: models.SplitExact, Splits: []models.ExpenseSplit{{UserID: 1, Amount: 10}, {UserID: 2, Amount: -5}}},
+			wantErr: true,
+		},
+		{
+			name:    "participant listed twice",
+			expense: models.Expense{Amount: 10, Splits: []models.ExpenseSplit{{UserID: 1}, {UserID: 1}}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown split type",
+			expense: models.Expense{Amount: 10, SplitType: "percent", Splits: []models.ExpenseSplit{{UserID: 1}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := computeSplits(&tt.expense, tt.participants)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("computeSplits() = %+v, want an error", tt.expense.Splits)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("computeSplits() error = %v", err)
+			}
+
+			users := []uint{}
+			amounts := []float64{}
+			var total int64
+			for _, split := range tt.expense.Splits {
+				users = append(users, split.UserID)
+				amounts = append(amounts, split.Amount)
+				total += toCents(split.Amount)
+			}
+			if !reflect.DeepEqual(users, tt.wantUsers) || !reflect.DeepEqual(amounts, tt.wantAmounts) {
+				t.Errorf("splits = %v %v, want %v %v", users, amounts, tt.wantUsers, tt.wantAmounts)
+			}
+			if total != toCents(tt.expense.Amount) {
+				t.Errorf("splits total %d cents, want %d", total, toCents(tt.expense.Amount))
+			}
+		})
+	}
+}
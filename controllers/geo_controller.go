@@ -0,0 +1,335 @@
+package controllers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"travelmate-api/geo"
+	"travelmate-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultRadiusKm = 50.0
+	// Un peu moins de la moitié de la circonférence terrestre, au-delà le cercle couvre tout le globe
+	maxRadiusKm = 20000.0
+)
+
+// validateTripCoordinates vérifie les coordonnées d'un voyage
+func validateTripCoordinates(trip *models.Trip) error {
+	return geo.ValidateCoordinates(trip.Latitude, trip.Longitude)
+}
+
+// validateCoordinateUpdate vérifie les coordonnées d'une mise à jour groupée, indexée par colonne (normalizeTripUpdate)
+func validateCoordinateUpdate(update map[string]interface{}) error {
+	for field, bound := range map[string]float64{"latitude": 90, "longitude": 180} {
+		value, ok := update[field]
+		if !ok {
+			continue
+		}
+		number, ok := value.(float64)
+		if !ok || number < -bound || number > bound {
+			return errors.New("Coordonnées invalides : la latitude doit être comprise entre -90 et 90 et la longitude entre -180 et 180")
+		}
+	}
+	return nil
+}
+
+// parseCoordinate lit une coordonnée dans les paramètres de la requête
+func parseCoordinate(c *gin.Context, name string) (float64, bool, error) {
+	value := c.Query(name)
+	if value == "" {
+		return 0, false, nil
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false, errors.New("Le paramètre '" + name + "' doit être un nombre")
+	}
+	return number, true, nil
+}
+
+// inBBox limite la requête aux voyages situés dans le rectangle, en s'appuyant sur l'index
+// des coordonnées. Les voyages sans coordonnées (0, 0) sont exclus.
+func inBBox(query *gorm.DB, box geo.BBox) *gorm.DB {
+	query = query.
+		Where("NOT (trips.latitude = 0 AND trips.longitude = 0)").
+		Where("trips.latitude BETWEEN ? AND ?", box.MinLat, box.MaxLat)
+	if box.MinLng <= box.MaxLng {
+		return query.Where("trips.longitude BETWEEN ? AND ?", box.MinLng, box.MaxLng)
+	}
+	return query.Where("trips.longitude >= ? OR trips.longitude <= ?", box.MinLng, box.MaxLng)
+}
+
+// distanceKmSQL est la formule de haversine (geo.DistanceKm) appliquée aux coordonnées du voyage,
+// paramétrée par la latitude, la latitude et la longitude du point de recherche
+const distanceKmSQL = "2 * ? * asin(min(1, sqrt(" +
+	"power(sin(radians(trips.latitude - ?) / 2), 2) + " +
+	"cos(radians(?)) * cos(radians(trips.latitude)) * power(sin(radians(trips.longitude - ?) / 2), 2))))"
+
+// respondByDistance répond avec la page demandée des voyages pré-filtrés par le rectangle et
+// situés à moins de radiusKm du point (sans limite si radiusKm est infini), triés du plus
+// proche au plus lointain. Le calcul, le tri et la pagination sont faits par la base.
+func respondByDistance(c *gin.Context, query *gorm.DB, box geo.BBox, lat, lng, radiusKm float64) {
+	opts, err := parseListOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	distance := gorm.Expr(distanceKmSQL, geo.EarthRadiusKm, lat, lat, lng)
+	query = inBBox(opts.filter(query), box)
+	if !math.IsInf(radiusKm, 1) {
+		query = query.Where("? <= ?", distance, radiusKm)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération"})
+		return
+	}
+
+	results := []models.TripDistance{}
+	err = query.
+		Select("trips.*, round(?, 2) AS distance_km", distance).
+		Order("distance_km, trips.id").
+		Limit(opts.Limit).
+		Offset((opts.Page - 1) * opts.Limit).
+		Scan(&results).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération"})
+		return
+	}
+
+	setPageHeaders(c, opts, total)
+	c.JSON(http.StatusOK, results)
+}
+
+// GetTripsNear godoc
+// @Summary Voyages à proximité
+// @Description Retourne les voyages visibles par l'utilisateur situés à moins de radiusKm du point donné, du plus proche au plus lointain. Les voyages sans coordonnées sont ignorés.
+// @Tags Trips
+// @Produce json
+// @Param lat query number true "Latitude du point"
+// @Param lng query number true "Longitude du point"
+// @Param radiusKm query number false "Rayon de recherche en kilomètres (50 par défaut)"
+// @Param userId query int false "ID de l'utilisateur (administrateurs uniquement)"
+// @Param all query bool false "Tous les voyages (administrateurs uniquement)"
+// @Param page query int false "Numéro de page (1 par défaut)"
+// @Param limit query int false "Nombre de voyages par page (20 par défaut, 100 au maximum)"
+// @Param from query string false "Voyages se terminant à partir de cette date (AAAA-MM-JJ)"
+// @Param to query string false "Voyages commençant au plus tard à cette date (AAAA-MM-JJ)"
+// @Param status query string false "Statut du voyage (upcoming, ongoing ou past)"
+// @Success 200 {array} models.TripDistance
+// @Header 200 {integer} X-Total-Count "Nombre total de voyages correspondants"
+// @Header 200 {string} Link "Liens vers les pages first, prev, next et last"
+// @Failure 400 {object} map[string]string "Paramètre invalide"
+// @Failure 403 {object} map[string]string "Accès refusé"
+// @Security BearerAuth
+// @Router /trips/near [get]
+func GetTripsNear(c *gin.Context) {
+	lat, hasLat, err := parseCoordinate(c, "lat")
+	if err == nil && !hasLat {
+		err = errors.New("Le paramètre 'lat' est requis")
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	lng, hasLng, err := parseCoordinate(c, "lng")
+	if err == nil && !hasLng {
+		err = errors.New("Le paramètre 'lng' est requis")
+	}
+	if err == nil {
+		err = geo.ValidateCoordinates(lat, lng)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	radiusKm := defaultRadiusKm
+	if value := c.Query("radiusKm"); value != "" {
+		radiusKm, err = strconv.ParseFloat(value, 64)
+		if err != nil || radiusKm <= 0 || radiusKm > maxRadiusKm {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Le paramètre 'radiusKm' doit être un nombre positif d'au plus 20000 km"})
+			return
+		}
+	}
+
+	query, ok := scopedTrips(c, c.Query("userId"))
+	if !ok {
+		return
+	}
+	respondByDistance(c, query, geo.Around(lat, lng, radiusKm), lat, lng, radiusKm)
+}
+
+// GetTripsWithin godoc
+// @Summary Voyages dans une zone
+// @Description Retourne les voyages visibles par l'utilisateur situés dans le rectangle donné, triés par distance au point lat/lng ou, à défaut, au centre du rectangle. Un rectangle dont minLng est supérieur à maxLng traverse l'antiméridien.
+// @Tags Trips
+// @Produce json
+// @Param bbox query string true "Rectangle minLng,minLat,maxLng,maxLat"
+// @Param lat query number false "Latitude du point de référence pour le tri"
+// @Param lng query number false "Longitude du point de référence pour le tri"
+// @Param userId query int false "ID de l'utilisateur (administrateurs uniquement)"
+// @Param all query bool false "Tous les voyages (administrateurs uniquement)"
+// @Param page query int false "Numéro de page (1 par défaut)"
+// @Param limit query int false "Nombre de voyages par page (20 par défaut, 100 au maximum)"
+// @Param from query string false "Voyages se terminant à partir de cette date (AAAA-MM-JJ)"
+// @Param to query string false "Voyages commençant au plus tard à cette date (AAAA-MM-JJ)"
+// @Param status query string false "Statut du voyage (upcoming, ongoing ou past)"
+// @Success 200 {array} models.TripDistance
+// @Header 200 {integer} X-Total-Count "Nombre total de voyages correspondants"
+// @Header 200 {string} Link "Liens vers les pages first, prev, next et last"
+// @Failure 400 {object} map[string]string "Paramètre invalide"
+// @Failure 403 {object} map[string]string "Accès refusé"
+// @Security BearerAuth
+// @Router /trips/within [get]
+func GetTripsWithin(c *gin.Context) {
+	if c.Query("bbox") == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le paramètre 'bbox' est requis"})
+		return
+	}
+	box, err := geo.ParseBBox(c.Query("bbox"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lat, lng := box.Center()
+	refLat, hasLat, err := parseCoordinate(c, "lat")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	refLng, hasLng, err := parseCoordinate(c, "lng")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if hasLat && hasLng {
+		if err := geo.ValidateCoordinates(refLat, refLng); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		lat, lng = refLat, refLng
+	}
+
+	query, ok := scopedTrips(c, c.Query("userId"))
+	if !ok {
+		return
+	}
+	respondByDistance(c, query, box, lat, lng, math.Inf(1))
+}
+
+const (
+	// Taille en pixels des cases de regroupement des voyages sur la carte
+	clusterCellPx = 60
+	// À partir de ce niveau de zoom, les voyages ne sont plus regroupés
+	maxClusterZoom = 17
+)
+
+// tripFeature convertit un voyage en objet GeoJSON
+func tripFeature(trip *models.Trip, today string) geo.Feature {
+	feature := geo.NewPointFeature(trip.Latitude, trip.Longitude, map[string]interface{}{
+		"title":     trip.Title,
+		"location":  trip.Location,
+		"startDate": trip.StartDate,
+		"endDate":   trip.EndDate,
+		"status":    tripStatus(trip, today),
+	})
+	feature.ID = trip.ID
+	return feature
+}
+
+// GetTripsGeoJSON godoc
+// @Summary Voyages au format GeoJSON
+// @Description Retourne les voyages localisés visibles par l'utilisateur sous forme de FeatureCollection GeoJSON (RFC 7946), avec leur titre, leurs dates et leur statut. Avec zoom, les voyages proches à ce niveau de zoom sont regroupés en un seul point (propriétés cluster et pointCount, bbox du groupe) ; au-delà du zoom 16, ils ne sont plus regroupés.
+// @Tags Trips
+// @Produce json
+// @Param bbox query string false "Rectangle minLng,minLat,maxLng,maxLat"
+// @Param zoom query int false "Niveau de zoom de la carte (0 à 22) pour le regroupement"
+// @Param userId query int false "ID de l'utilisateur (administrateurs uniquement)"
+// @Param all query bool false "Tous les voyages (administrateurs uniquement)"
+// @Param from query string false "Voyages se terminant à partir de cette date (AAAA-MM-JJ)"
+// @Param to query string false "Voyages commençant au plus tard à cette date (AAAA-MM-JJ)"
+// @Param location query string false "Localisation (correspondance partielle)"
+// @Param status query string false "Statut du voyage (upcoming, ongoing ou past)"
+// @Success 200 {object} geo.FeatureCollection
+// @Failure 400 {object} map[string]string "Paramètre invalide"
+// @Failure 403 {object} map[string]string "Accès refusé"
+// @Security BearerAuth
+// @Router /trips.geojson [get]
+func GetTripsGeoJSON(c *gin.Context) {
+	box := geo.BBox{MinLng: -180, MinLat: -90, MaxLng: 180, MaxLat: 90}
+	if value := c.Query("bbox"); value != "" {
+		var err error
+		if box, err = geo.ParseBBox(value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	zoom := -1
+	if value := c.Query("zoom"); value != "" {
+		var err error
+		if zoom, err = strconv.Atoi(value); err != nil || zoom < 0 || zoom > 22 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Le paramètre 'zoom' doit être un entier entre 0 et 22"})
+			return
+		}
+	}
+
+	query, ok := scopedTrips(c, c.Query("userId"))
+	if !ok {
+		return
+	}
+	opts, err := parseListOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var trips []models.Trip
+	if err := inBBox(opts.filter(query), box).Order("trips.start_date, trips.id").Find(&trips).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des voyages"})
+		return
+	}
+
+	today := time.Now().Format(dateLayout)
+	collection := geo.NewFeatureCollection()
+	if c.Query("bbox") != "" {
+		collection.BBox = box.GeoJSON()
+	}
+
+	if zoom < 0 || zoom >= maxClusterZoom {
+		for i := range trips {
+			collection.Features = append(collection.Features, tripFeature(&trips[i], today))
+		}
+	} else {
+		lats := make([]float64, len(trips))
+		lngs := make([]float64, len(trips))
+		for i := range trips {
+			lats[i], lngs[i] = trips[i].Latitude, trips[i].Longitude
+		}
+		for _, cluster := range geo.ClusterPoints(lats, lngs, zoom, clusterCellPx) {
+			if len(cluster.Indexes) == 1 {
+				collection.Features = append(collection.Features, tripFeature(&trips[cluster.Indexes[0]], today))
+				continue
+			}
+			feature := geo.NewPointFeature(cluster.Latitude, cluster.Longitude, map[string]interface{}{
+				"cluster":    true,
+				"pointCount": len(cluster.Indexes),
+			})
+			feature.BBox = cluster.BBox.GeoJSON()
+			collection.Features = append(collection.Features, feature)
+		}
+	}
+
+	c.Header("Content-Type", "application/geo+json")
+	c.JSON(http.StatusOK, collection)
+}
@@ -0,0 +1,216 @@
+package controllers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"travelmate-api/geo"
+	"travelmate-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Formats d'export géographique
+const (
+	formatGPX = "gpx"
+	formatKML = "kml"
+)
+
+var geoContentTypes = map[string]string{
+	formatGPX: "application/gpx+xml",
+	formatKML: "application/vnd.google-earth.kml+xml",
+}
+
+// hasCoordinates indique si un lieu est localisé ; (0, 0) signifie « sans coordonnées »
+func hasCoordinates(lat, lng float64) bool {
+	return lat != 0 || lng != 0
+}
+
+// appendTrip ajoute au document le voyage et ses étapes localisés, ainsi qu'une route
+// reliant les étapes dans l'ordre chronologique du programme
+func appendTrip(doc *geo.Document, trip *models.Trip, days []models.ItineraryDay) {
+	if hasCoordinates(trip.Latitude, trip.Longitude) {
+		doc.Waypoints = append(doc.Waypoints, geo.Point{
+			Name:        trip.Title,
+			Description: trip.Description,
+			Kind:        "trip",
+			Latitude:    trip.Latitude,
+			Longitude:   trip.Longitude,
+			Begin:       trip.StartDate,
+			End:         trip.EndDate,
+		})
+	}
+
+	route := geo.Route{Name: trip.Title}
+	for _, day := range days {
+		for _, stop := range day.Stops {
+			if !hasCoordinates(stop.Latitude, stop.Longitude) {
+				continue
+			}
+			point := geo.Point{
+				Name:        stop.Name,
+				Description: stop.Notes,
+				Kind:        "stop",
+				Latitude:    stop.Latitude,
+				Longitude:   stop.Longitude,
+				Begin:       day.Date,
+				End:         day.Date,
+			}
+			doc.Waypoints = append(doc.Waypoints, point)
+			route.Points = append(route.Points, point)
+		}
+	}
+	if len(route.Points) > 1 {
+		doc.Routes = append(doc.Routes, route)
+	}
+}
+
+// writeGeoDocument envoie le document au format demandé, en pièce jointe
+func writeGeoDocument(c *gin.Context, doc *geo.Document, format, filename string) {
+	var buf bytes.Buffer
+	var err error
+	if format == formatKML {
+		err = geo.WriteKML(&buf, doc)
+	} else {
+		err = geo.WriteGPX(&buf, doc)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de l'export"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, filename, format))
+	c.Data(http.StatusOK, geoContentTypes[format], buf.Bytes())
+}
+
+// exportTrips exporte les voyages visibles par l'utilisateur : chaque voyage et ses étapes,
+// et une route « Historique des voyages » reliant les voyages par date de début
+func exportTrips(c *gin.Context, format string) {
+	query, ok := scopedTrips(c, c.Query("userId"))
+	if !ok {
+		return
+	}
+	opts, err := parseListOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var trips []models.Trip
+	if err := opts.filter(query).Order("trips.start_date, trips.id").Find(&trips).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des voyages"})
+		return
+	}
+
+	doc := &geo.Document{Name: "Voyages TravelMate"}
+	history := geo.Route{Name: "Historique des voyages"}
+	for i := range trips {
+		days, err := loadItinerary(trips[i].ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération du programme"})
+			return
+		}
+		appendTrip(doc, &trips[i], days)
+
+		if hasCoordinates(trips[i].Latitude, trips[i].Longitude) {
+			history.Points = append(history.Points, geo.Point{
+				Name:      trips[i].Title,
+				Latitude:  trips[i].Latitude,
+				Longitude: trips[i].Longitude,
+			})
+		}
+	}
+	if len(history.Points) > 1 {
+		doc.Routes = append([]geo.Route{history}, doc.Routes...)
+	}
+
+	writeGeoDocument(c, doc, format, "voyages")
+}
+
+// exportTrip exporte un voyage, ses étapes et la route qui les relie
+func exportTrip(c *gin.Context, format string) {
+	trip, ok := loadTrip(c, models.RoleViewer)
+	if !ok {
+		return
+	}
+	days, err := loadItinerary(trip.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération du programme"})
+		return
+	}
+
+	doc := &geo.Document{Name: trip.Title}
+	appendTrip(doc, trip, days)
+	writeGeoDocument(c, doc, format, fmt.Sprintf("trip-%d", trip.ID))
+}
+
+// ExportTripsGPX godoc
+// @Summary Exporter les voyages au format GPX
+// @Description Retourne un fichier GPX 1.1 avec un waypoint par voyage et par étape localisés, une route reliant les voyages par ordre chronologique et une route par voyage reliant ses étapes. Les filtres de la liste des voyages s'appliquent.
+// @Tags Export
+// @Produce application/gpx+xml
+// @Param userId query int false "ID de l'utilisateur (administrateurs uniquement)"
+// @Param all query bool false "Tous les voyages (administrateurs uniquement)"
+// @Param from query string false "Voyages se terminant à partir de cette date (AAAA-MM-JJ)"
+// @Param to query string false "Voyages commençant au plus tard à cette date (AAAA-MM-JJ)"
+// @Param location query string false "Localisation (correspondance partielle)"
+// @Param status query string false "Statut du voyage (upcoming, ongoing ou past)"
+// @Success 200 {string} string "Fichier GPX"
+// @Failure 400 {object} map[string]string "Paramètre invalide"
+// @Failure 403 {object} map[string]string "Accès refusé"
+// @Security BearerAuth
+// @Router /trips/export.gpx [get]
+func ExportTripsGPX(c *gin.Context) {
+	exportTrips(c, formatGPX)
+}
+
+// ExportTripsKML godoc
+// @Summary Exporter les voyages au format KML
+// @Description Retourne un fichier KML 2.2 (Google Earth) avec un repère daté par voyage et par étape localisés, une ligne reliant les voyages par ordre chronologique et une ligne par voyage reliant ses étapes. Les filtres de la liste des voyages s'appliquent.
+// @Tags Export
+// @Produce application/vnd.google-earth.kml+xml
+// @Param userId query int false "ID de l'utilisateur (administrateurs uniquement)"
+// @Param all query bool false "Tous les voyages (administrateurs uniquement)"
+// @Param from query string false "Voyages se terminant à partir de cette date (AAAA-MM-JJ)"
+// @Param to query string false "Voyages commençant au plus tard à cette date (AAAA-MM-JJ)"
+// @Param location query string false "Localisation (correspondance partielle)"
+// @Param status query string false "Statut du voyage (upcoming, ongoing ou past)"
+// @Success 200 {string} string "Fichier KML"
+// @Failure 400 {object} map[string]string "Paramètre invalide"
+// @Failure 403 {object} map[string]string "Accès refusé"
+// @Security BearerAuth
+// @Router /trips/export.kml [get]
+func ExportTripsKML(c *gin.Context) {
+	exportTrips(c, formatKML)
+}
+
+// ExportTripGPX godoc
+// @Summary Exporter un voyage au format GPX
+// @Description Retourne un fichier GPX 1.1 avec le voyage et ses étapes localisés, et une route reliant les étapes dans l'ordre du programme.
+// @Tags Export
+// @Produce application/gpx+xml
+// @Param id path int true "ID du voyage"
+// @Success 200 {string} string "Fichier GPX"
+// @Failure 403 {object} map[string]string "Accès refusé"
+// @Failure 404 {object} map[string]string "Voyage non trouvé"
+// @Security BearerAuth
+// @Router /trips/{id}/export.gpx [get]
+func ExportTripGPX(c *gin.Context) {
+	exportTrip(c, formatGPX)
+}
+
+// ExportTripKML godoc
+// @Summary Exporter un voyage au format KML
+// @Description Retourne un fichier KML 2.2 (Google Earth) avec le voyage et ses étapes localisés, et une ligne reliant les étapes dans l'ordre du programme.
+// @Tags Export
+// @Produce application/vnd.google-earth.kml+xml
+// @Param id path int true "ID du voyage"
+// @Success 200 {string} string "Fichier KML"
+// @Failure 403 {object} map[string]string "Accès refusé"
+// @Failure 404 {object} map[string]string "Voyage non trouvé"
+// @Security BearerAuth
+// @Router /trips/{id}/export.kml [get]
+func ExportTripKML(c *gin.Context) {
+	exportTrip(c, formatKML)
+}
@@ -0,0 +1,242 @@
+package controllers
+
+import (
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"travelmate-api/database"
+	"travelmate-api/ics"
+	"travelmate-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Taille maximale d'un fichier importé
+const maxImportSize = 5 << 20
+
+// Marge laissée aux autres champs et aux en-têtes du formulaire multipart
+const importFormOverhead = 64 << 10
+
+// importFile retourne le fichier envoyé dans le champ file. Le corps de la requête est limité
+// pendant sa lecture : un envoi trop volumineux est refusé sans être chargé en entier.
+func importFile(c *gin.Context) (*multipart.FileHeader, bool) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize+importFormOverhead)
+	header, err := c.FormFile("file")
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) || (err == nil && header.Size > maxImportSize) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Le fichier ne doit pas dépasser 5 Mo"})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le fichier est requis (champ file)"})
+		return nil, false
+	}
+	return header, true
+}
+
+// importCandidate est un voyage lu dans un fichier importé, ou l'erreur de lecture de sa ligne
+type importCandidate struct {
+	Row  int
+	Trip *models.Trip
+	Err  error
+}
+
+// tripKey identifie un voyage pour la détection des doublons : même titre (sans tenir
+// compte de la casse) et mêmes dates
+func tripKey(trip *models.Trip) string {
+	return strings.ToLower(strings.TrimSpace(trip.Title)) + "|" + trip.StartDate + "|" + trip.EndDate
+}
+
+// validateImportedTrip vérifie les champs obligatoires, les dates et les coordonnées d'un voyage importé
+func validateImportedTrip(trip *models.Trip) error {
+	if strings.TrimSpace(trip.Title) == "" {
+		return errors.New("Le titre est requis")
+	}
+	start, err := time.Parse(dateLayout, trip.StartDate)
+	if err != nil {
+		return errors.New("La date de début doit être au format AAAA-MM-JJ")
+	}
+	end, err := time.Parse(dateLayout, trip.EndDate)
+	if err != nil {
+		return errors.New("La date de fin doit être au format AAAA-MM-JJ")
+	}
+	if end.Before(start) {
+		return errors.New("La date de fin doit être postérieure à la date de début")
+	}
+	return validateTripCoordinates(trip)
+}
+
+// runImport valide les voyages lus, écarte les doublons (voyages existants de l'utilisateur
+// ou déjà présents plus haut dans le fichier) puis crée les autres, sauf en simulation.
+// En mode atomique, rien n'est créé si une ligne est invalide.
+func runImport(c *gin.Context, candidates []importCandidate, dryRun, atomic bool) {
+	userID := c.GetUint("user_id")
+
+	var existing []models.Trip
+	if err := database.DB.Select("id", "title", "start_date", "end_date").Where("user_id = ?", userID).Find(&existing).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la recherche des doublons"})
+		return
+	}
+	known := map[string]uint{}
+	for i := range existing {
+		known[tripKey(&existing[i])] = existing[i].ID
+	}
+	// Les voyages du fichier n'ont pas encore d'ID : 0 désigne un doublon interne au fichier
+	inFile := map[string]bool{}
+
+	report := models.ImportReport{DryRun: dryRun, Rows: []models.ImportRow{}}
+	var toCreate []*models.Trip
+	for _, candidate := range candidates {
+		row := models.ImportRow{Row: candidate.Row, Trip: candidate.Trip}
+		err := candidate.Err
+		if err == nil {
+			candidate.Trip.ID = 0
+			candidate.Trip.UserID = userID
+			err = validateImportedTrip(candidate.Trip)
+		}
+
+		if err != nil {
+			row.Status = models.ImportInvalid
+			row.Error = err.Error()
+			report.Invalid++
+		} else if id, ok := known[tripKey(candidate.Trip)]; ok || inFile[tripKey(candidate.Trip)] {
+			row.Status = models.ImportDuplicate
+			row.DuplicateOf = id
+			report.Duplicates++
+		} else {
+			row.Status = models.ImportCreated
+			inFile[tripKey(candidate.Trip)] = true
+			toCreate = append(toCreate, candidate.Trip)
+			report.Created++
+		}
+		report.Rows = append(report.Rows, row)
+	}
+
+	if dryRun {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+	if atomic && report.Invalid > 0 {
+		report.Created = 0
+		for i := range report.Rows {
+			if report.Rows[i].Status == models.ImportCreated {
+				report.Rows[i].Status = models.ImportValid
+			}
+		}
+		c.JSON(http.StatusUnprocessableEntity, report)
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		for _, trip := range toCreate {
+			if err := createTrip(tx, trip); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la création des voyages"})
+		return
+	}
+
+	status := http.StatusOK
+	if report.Created > 0 {
+		status = http.StatusCreated
+	}
+	c.JSON(status, report)
+}
+
+// eventToTrip convertit un événement en voyage. Les heures en UTC sont ramenées au fuseau
+// donné avant d'en extraire les dates ; un événement se terminant à minuit ne déborde pas
+// sur le jour suivant.
+func eventToTrip(event *ics.Event, tz *time.Location) *models.Trip {
+	start, end := event.Start, event.End
+	if !event.AllDay {
+		if start.Location() == time.UTC {
+			start, end = start.In(tz), end.In(tz)
+		}
+		if end.After(start) {
+			end = end.Add(-time.Nanosecond)
+		}
+	}
+
+	trip := &models.Trip{
+		Title:       strings.TrimSpace(event.Summary),
+		Description: event.Description,
+		Location:    event.Location,
+		StartDate:   start.Format(dateLayout),
+		EndDate:     end.Format(dateLayout),
+		Notes:       event.URL,
+	}
+	if event.HasGeo {
+		trip.Latitude, trip.Longitude = event.Latitude, event.Longitude
+	}
+	return trip
+}
+
+// ImportTripsICS godoc
+// @Summary Importer des voyages depuis un fichier iCalendar
+// @Description Crée un voyage pour chaque événement (VEVENT) du fichier .ics envoyé : réservations de vol, d'hôtel... Les journées entières, les fuseaux horaires (TZID) et les coordonnées (GEO) sont pris en compte. Les événements identiques à un voyage existant de l'utilisateur (même titre et mêmes dates) sont signalés comme doublons et ne sont pas créés. Avec dryRun=true, rien n'est créé et le rapport indique ce qui le serait.
+// @Tags Import
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Fichier iCalendar (.ics, 5 Mo au maximum)"
+// @Param dryRun query bool false "Simulation, sans création"
+// @Param timezone query string false "Fuseau horaire IANA utilisé pour les dates des événements en UTC (UTC par défaut)"
+// @Success 200 {object} models.ImportReport "Simulation, ou aucun voyage créé"
+// @Success 201 {object} models.ImportReport "Voyages créés"
+// @Failure 400 {object} map[string]string "Fichier manquant ou invalide"
+// @Failure 413 {object} map[string]string "Fichier trop volumineux"
+// @Security BearerAuth
+// @Router /trips/import/ics [post]
+func ImportTripsICS(c *gin.Context) {
+	tz := time.UTC
+	if name := c.Query("timezone"); name != "" {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Fuseau horaire inconnu"})
+			return
+		}
+		tz = loc
+	}
+
+	header, ok := importFile(c)
+	if !ok {
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Fichier illisible"})
+		return
+	}
+	defer file.Close()
+
+	events, failures, err := ics.Parse(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Les événements sont numérotés dans l'ordre du fichier, y compris ceux qui sont illisibles
+	failed := map[int]error{}
+	for _, failure := range failures {
+		failed[failure.Index] = failure.Err
+	}
+	candidates := make([]importCandidate, 0, len(events)+len(failures))
+	next := 0
+	for row := 1; row <= len(events)+len(failures); row++ {
+		if err, ok := failed[row]; ok {
+			candidates = append(candidates, importCandidate{Row: row, Err: err})
+			continue
+		}
+		candidates = append(candidates, importCandidate{Row: row, Trip: eventToTrip(&events[next], tz)})
+		next++
+	}
+
+	runImport(c, candidates, c.Query("dryRun") == "true", false)
+}
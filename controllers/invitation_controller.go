@@ -0,0 +1,352 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"travelmate-api/database"
+	"travelmate-api/logger"
+	"travelmate-api/mailer"
+	"travelmate-api/models"
+	"travelmate-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// appURL retourne l'adresse du front utilisée dans les liens envoyés par email
+func appURL() string {
+	if url := os.Getenv("APP_URL"); url != "" {
+		return strings.TrimRight(url, "/")
+	}
+	return "http://localhost:4200"
+}
+
+// invitationTTL retourne la durée de validité d'une invitation (INVITATION_TTL_HOURS, 7 jours par défaut)
+func invitationTTL() time.Duration {
+	if hours, err := strconv.Atoi(os.Getenv("INVITATION_TTL_HOURS")); err == nil && hours > 0 {
+		return time.Duration(hours) * time.Hour
+	}
+	return 7 * 24 * time.Hour
+}
+
+// sendInvitation envoie par email le lien permettant d'accepter l'invitation
+func sendInvitation(invitation *models.Invitation, trip *models.Trip, inviter *models.User) error {
+	token, err := utils.GenerateInvitationToken(invitation.ID, invitation.Email, invitation.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf(
+		"Bonjour,\n\n%s vous invite à rejoindre le voyage \"%s\" sur TravelMate.\n\n"+
+			"Pour accepter l'invitation, connectez-vous puis ouvrez le lien suivant :\n%s/invitations/accept?token=%s\n\n"+
+			"Cette invitation expire le %s.",
+		inviter.Name, trip.Title, appURL(), token, invitation.ExpiresAt.Format("02/01/2006 à 15:04"),
+	)
+	return mailer.Send(mailer.Message{
+		To:      invitation.Email,
+		Subject: "Invitation au voyage " + trip.Title,
+		Body:    body,
+	})
+}
+
+// respondToInvitation accepte ou refuse une invitation au nom de l'utilisateur connecté.
+// L'invitation doit être en attente, non expirée et adressée à l'email de l'utilisateur.
+func respondToInvitation(c *gin.Context, invitation *models.Invitation, accept bool) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Cette invitation ne vous est pas adressée"})
+		return
+	}
+	if invitation.Status != models.InvitationPending {
+		c.JSON(http.StatusConflict, gin.H{"error": "Cette invitation a déjà été traitée"})
+		return
+	}
+	if time.Now().After(invitation.ExpiresAt) {
+		c.JSON(http.StatusGone, gin.H{"error": "Cette invitation a expiré"})
+		return
+	}
+
+	now := time.Now()
+	invitation.RespondedAt = &now
+	invitation.Status = models.InvitationDeclined
+	if accept {
+		invitation.Status = models.InvitationAccepted
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Trip").Save(invitation).Error; err != nil {
+			return err
+		}
+		if !accept {
+			return nil
+		}
+
+		var count int64
+		tx.Model(&models.TripMember{}).Where("trip_id = ? AND user_id = ?", invitation.TripID, user.ID).Count(&count)
+		if count > 0 {
+			return nil
+		}
+		member := models.TripMember{TripID: invitation.TripID, UserID: user.ID, Role: invitation.Role}
+		return tx.Omit("User").Create(&member).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la réponse à l'invitation"})
+		return
+	}
+	c.JSON(http.StatusOK, invitation)
+}
+
+// CreateInvitation godoc
+// @Summary Inviter une personne sur un voyage
+// @Description Crée une invitation signée et limitée dans le temps pour une adresse email, et l'envoie par email. Réservé aux propriétaires du voyage.
+// @Tags Invitations
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Param invitation body object{email=string,role=string} true "Email invité et rôle proposé (viewer par défaut)"
+// @Success 201 {object} models.Invitation
+// @Failure 400 {object} map[string]string "Format invalide"
+// @Failure 403 {object} map[string]string "Accès refusé"
+// @Failure 409 {object} map[string]string "Cette personne est déjà membre du voyage"
+// @Security BearerAuth
+// @Router /trips/{id}/invitations [post]
+func CreateInvitation(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleOwner)
+	if !ok {
+		return
+	}
+	inviter, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Email string `json:"email" binding:"required,email"`
+		Role  string `json:"role"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Format invalide : email requis"})
+		return
+	}
+	input.Email = strings.ToLower(strings.TrimSpace(input.Email))
+	if input.Role == "" {
+		input.Role = models.RoleViewer
+	}
+	if models.RoleRank(input.Role) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Rôle invalide (owner, editor ou viewer)"})
+		return
+	}
+
+	var count int64
+	database.DB.Model(&models.TripMember{}).
+		Joins("JOIN users ON users.id = trip_members.user_id").
+		Where("trip_members.trip_id = ? AND LOWER(users.email) = ?", trip.ID, input.Email).
+		Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Cette personne est déjà membre du voyage"})
+		return
+	}
+
+	// Une nouvelle invitation remplace celle qui serait encore en attente
+	invitation := models.Invitation{
+		TripID:      trip.ID,
+		Email:       input.Email,
+		Role:        input.Role,
+		InvitedByID: inviter.ID,
+		Status:      models.InvitationPending,
+		ExpiresAt:   time.Now().Add(invitationTTL()),
+	}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.Invitation{}).
+			Where("trip_id = ? AND email = ? AND status = ?", trip.ID, input.Email, models.InvitationPending).
+			Update("status", models.InvitationRevoked).Error
+		if err != nil {
+			return err
+		}
+		return tx.Omit("Trip").Create(&invitation).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la création de l'invitation"})
+		return
+	}
+
+	if err := sendInvitation(&invitation, trip, inviter); err != nil {
+		logger.ErrorLogger.Printf("Envoi de l'invitation %d impossible : %v", invitation.ID, err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "L'invitation a été créée mais l'email n'a pas pu être envoyé"})
+		return
+	}
+	c.JSON(http.StatusCreated, invitation)
+}
+
+// GetTripInvitations godoc
+// @Summary Invitations d'un voyage
+// @Description Retourne les invitations envoyées pour un voyage. Réservé aux propriétaires du voyage.
+// @Tags Invitations
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Success 200 {array} models.Invitation
+// @Failure 403 {object} map[string]string "Accès refusé"
+// @Security BearerAuth
+// @Router /trips/{id}/invitations [get]
+func GetTripInvitations(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleOwner)
+	if !ok {
+		return
+	}
+
+	invitations := []models.Invitation{}
+	if err := database.DB.Where("trip_id = ?", trip.ID).Order("created_at DESC").Find(&invitations).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des invitations"})
+		return
+	}
+	c.JSON(http.StatusOK, invitations)
+}
+
+// RevokeInvitation godoc
+// @Summary Annuler une invitation
+// @Description Annule une invitation encore en attente. Réservé aux propriétaires du voyage.
+// @Tags Invitations
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Param invitation path int true "ID de l'invitation"
+// @Success 200 {object} map[string]string "L'invitation a bien été annulée"
+// @Failure 404 {object} map[string]string "Invitation non trouvée"
+// @Failure 409 {object} map[string]string "Cette invitation a déjà été traitée"
+// @Security BearerAuth
+// @Router /trips/{id}/invitations/{invitation} [delete]
+func RevokeInvitation(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleOwner)
+	if !ok {
+		return
+	}
+
+	var invitation models.Invitation
+	if err := database.DB.Where("trip_id = ?", trip.ID).First(&invitation, paramID(c, "invitation")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invitation non trouvée"})
+		return
+	}
+	if invitation.Status != models.InvitationPending {
+		c.JSON(http.StatusConflict, gin.H{"error": "Cette invitation a déjà été traitée"})
+		return
+	}
+
+	if err := database.DB.Model(&invitation).Update("status", models.InvitationRevoked).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de l'annulation de l'invitation"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "L'invitation a bien été annulée"})
+}
+
+// GetMyInvitations godoc
+// @Summary Mes invitations
+// @Description Retourne les invitations en attente et non expirées adressées à l'email de l'utilisateur connecté
+// @Tags Invitations
+// @Produce json
+// @Success 200 {array} models.Invitation
+// @Security BearerAuth
+// @Router /invitations [get]
+func GetMyInvitations(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	invitations := []models.Invitation{}
+	err := database.DB.
+		Preload("Trip").
+		Where("LOWER(email) = ? AND status = ? AND expires_at > ?", strings.ToLower(user.Email), models.InvitationPending, time.Now()).
+		Order("created_at DESC").
+		Find(&invitations).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des invitations"})
+		return
+	}
+	c.JSON(http.StatusOK, invitations)
+}
+
+// AcceptInvitation godoc
+// @Summary Accepter une invitation
+// @Description Accepte une invitation adressée à l'utilisateur connecté et le rend membre du voyage
+// @Tags Invitations
+// @Produce json
+// @Param invitation path int true "ID de l'invitation"
+// @Success 200 {object} models.Invitation
+// @Failure 403 {object} map[string]string "Cette invitation ne vous est pas adressée"
+// @Failure 404 {object} map[string]string "Invitation non trouvée"
+// @Failure 409 {object} map[string]string "Cette invitation a déjà été traitée"
+// @Failure 410 {object} map[string]string "Cette invitation a expiré"
+// @Security BearerAuth
+// @Router /invitations/{invitation}/accept [post]
+func AcceptInvitation(c *gin.Context) {
+	var invitation models.Invitation
+	if err := database.DB.First(&invitation, paramID(c, "invitation")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invitation non trouvée"})
+		return
+	}
+	respondToInvitation(c, &invitation, true)
+}
+
+// DeclineInvitation godoc
+// @Summary Refuser une invitation
+// @Description Refuse une invitation adressée à l'utilisateur connecté
+// @Tags Invitations
+// @Produce json
+// @Param invitation path int true "ID de l'invitation"
+// @Success 200 {object} models.Invitation
+// @Failure 403 {object} map[string]string "Cette invitation ne vous est pas adressée"
+// @Failure 404 {object} map[string]string "Invitation non trouvée"
+// @Failure 409 {object} map[string]string "Cette invitation a déjà été traitée"
+// @Security BearerAuth
+// @Router /invitations/{invitation}/decline [post]
+func DeclineInvitation(c *gin.Context) {
+	var invitation models.Invitation
+	if err := database.DB.First(&invitation, paramID(c, "invitation")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invitation non trouvée"})
+		return
+	}
+	respondToInvitation(c, &invitation, false)
+}
+
+// AcceptInvitationToken godoc
+// @Summary Accepter une invitation depuis le lien reçu par email
+// @Description Vérifie le jeton signé reçu par email et accepte l'invitation correspondante
+// @Tags Invitations
+// @Accept json
+// @Produce json
+// @Param token body object{token=string} true "Jeton d'invitation"
+// @Success 200 {object} models.Invitation
+// @Failure 400 {object} map[string]string "Jeton d'invitation invalide ou expiré"
+// @Failure 403 {object} map[string]string "Cette invitation ne vous est pas adressée"
+// @Failure 409 {object} map[string]string "Cette invitation a déjà été traitée"
+// @Security BearerAuth
+// @Router /invitations/accept [post]
+func AcceptInvitationToken(c *gin.Context) {
+	var input struct {
+		Token string `json:"token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Format invalide : token requis"})
+		return
+	}
+
+	invitationID, email, err := utils.ParseInvitationToken(input.Token)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Jeton d'invitation invalide ou expiré"})
+		return
+	}
+
+	var invitation models.Invitation
+	if err := database.DB.First(&invitation, invitationID).Error; err != nil || !strings.EqualFold(invitation.Email, email) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Jeton d'invitation invalide ou expiré"})
+		return
+	}
+	respondToInvitation(c, &invitation, true)
+}
@@ -0,0 +1,490 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"travelmate-api/database"
+	"travelmate-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const dateLayout = "2006-01-02"
+const timeLayout = "15:04"
+
+// paramID convertit un paramètre de route en identifiant numérique
+func paramID(c *gin.Context, name string) uint {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return uint(id)
+}
+
+// loadDay récupère la journée :day appartenant au voyage donné
+func loadDay(c *gin.Context, trip *models.Trip) (*models.ItineraryDay, bool) {
+	var day models.ItineraryDay
+	if err := database.DB.Where("trip_id = ?", trip.ID).First(&day, paramID(c, "day")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Journée non trouvée"})
+		return nil, false
+	}
+	return &day, true
+}
+
+// tripDateRange retourne les dates de début et de fin du voyage ; une date non renseignée
+// reste nulle et ne limite pas le programme
+func tripDateRange(trip *models.Trip) (start, end time.Time, err error) {
+	if trip.StartDate != "" {
+		if start, err = time.Parse(dateLayout, trip.StartDate); err != nil {
+			return start, end, errors.New("Les dates du voyage doivent être au format AAAA-MM-JJ pour planifier son programme")
+		}
+	}
+	if trip.EndDate != "" {
+		if end, err = time.Parse(dateLayout, trip.EndDate); err != nil {
+			return start, end, errors.New("Les dates du voyage doivent être au format AAAA-MM-JJ pour planifier son programme")
+		}
+	}
+	return start, end, nil
+}
+
+// validateDay vérifie que la date de la journée est comprise entre les dates du voyage
+func validateDay(trip *models.Trip, day *models.ItineraryDay) error {
+	date, err := time.Parse(dateLayout, day.Date)
+	if err != nil {
+		return errors.New("La date doit être au format AAAA-MM-JJ")
+	}
+	start, end, err := tripDateRange(trip)
+	if err != nil {
+		return err
+	}
+	if !start.IsZero() && date.Before(start) {
+		return errors.New("La journée est antérieure au début du voyage")
+	}
+	if !end.IsZero() && date.After(end) {
+		return errors.New("La journée est postérieure à la fin du voyage")
+	}
+	return nil
+}
+
+// conflictingDays retourne les dates des journées du programme qui ne sont pas comprises
+// entre les dates du voyage, par exemple après leur modification
+func conflictingDays(trip *models.Trip) ([]string, error) {
+	var dates []string
+	if err := database.DB.Model(&models.ItineraryDay{}).Where("trip_id = ?", trip.ID).Order("date, position, id").Pluck("date", &dates).Error; err != nil {
+		return nil, err
+	}
+
+	conflicts := []string{}
+	for _, date := range dates {
+		if err := validateDay(trip, &models.ItineraryDay{Date: date}); err != nil {
+			conflicts = append(conflicts, date)
+		}
+	}
+	return conflicts, nil
+}
+
+// validateStop vérifie les horaires et les coordonnées d'une étape
+func validateStop(stop *models.Stop) error {
+	var start, end time.Time
+	var err error
+	if stop.StartTime != "" {
+		if start, err = time.Parse(timeLayout, stop.StartTime); err != nil {
+			return errors.New("L'heure de début doit être au format HH:MM")
+		}
+	}
+	if stop.EndTime != "" {
+		if end, err = time.Parse(timeLayout, stop.EndTime); err != nil {
+			return errors.New("L'heure de fin doit être au format HH:MM")
+		}
+	}
+	if stop.StartTime != "" && stop.EndTime != "" && end.Before(start) {
+		return errors.New("L'heure de fin doit être postérieure à l'heure de début")
+	}
+	if stop.Latitude < -90 || stop.Latitude > 90 || stop.Longitude < -180 || stop.Longitude > 180 {
+		return errors.New("Coordonnées invalides")
+	}
+	return nil
+}
+
+// loadItinerary retourne les journées d'un voyage avec leurs étapes, dans l'ordre
+func loadItinerary(tripID uint) ([]models.ItineraryDay, error) {
+	days := []models.ItineraryDay{}
+	err := database.DB.
+		Preload("Stops", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position, start_time, id")
+		}).
+		Where("trip_id = ?", tripID).
+		Order("date, position, id").
+		Find(&days).Error
+	return days, err
+}
+
+// nextPosition retourne la position suivant la dernière position utilisée
+func nextPosition(model interface{}, column string, value uint) int {
+	var max int
+	database.DB.Model(model).Where(column+" = ?", value).Select("COALESCE(MAX(position), 0)").Scan(&max)
+	return max + 1
+}
+
+// GetItinerary godoc
+// @Summary Programme d'un voyage
+// @Description Retourne les journées d'un voyage et leurs étapes, triées par date puis par position
+// @Tags Itinerary
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Success 200 {array} models.ItineraryDay
+// @Failure 404 {object} map[string]string "Voyage non trouvé"
+// @Security BearerAuth
+// @Router /trips/{id}/days [get]
+func GetItinerary(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleViewer)
+	if !ok {
+		return
+	}
+
+	days, err := loadItinerary(trip.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération du programme"})
+		return
+	}
+	c.JSON(http.StatusOK, days)
+}
+
+// CreateItineraryDay godoc
+// @Summary Ajouter une journée
+// @Description Ajoute une journée au programme du voyage, éventuellement avec ses étapes. La date doit être comprise entre les dates du voyage.
+// @Tags Itinerary
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Param day body models.ItineraryDay true "Journée"
+// @Success 201 {object} models.ItineraryDay
+// @Failure 400 {object} map[string]string "Format invalide"
+// @Failure 404 {object} map[string]string "Voyage non trouvé"
+// @Security BearerAuth
+// @Router /trips/{id}/days [post]
+func CreateItineraryDay(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleEditor)
+	if !ok {
+		return
+	}
+
+	var day models.ItineraryDay
+	if err := c.ShouldBindJSON(&day); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Format invalide"})
+		return
+	}
+	day.ID = 0
+	day.TripID = trip.ID
+
+	if err := validateDay(trip, &day); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	for i := range day.Stops {
+		stop := &day.Stops[i]
+		stop.ID = 0
+		if stop.Name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Le nom de l'étape est requis"})
+			return
+		}
+		if err := validateStop(stop); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if stop.Position == 0 {
+			stop.Position = i + 1
+		}
+	}
+	if day.Position == 0 {
+		day.Position = nextPosition(&models.ItineraryDay{}, "trip_id", trip.ID)
+	}
+
+	if err := database.DB.Create(&day).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la création de la journée"})
+		return
+	}
+	c.JSON(http.StatusCreated, day)
+}
+
+// GetItineraryDay godoc
+// @Summary Récupérer une journée
+// @Description Retourne une journée du programme avec ses étapes
+// @Tags Itinerary
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Param day path int true "ID de la journée"
+// @Success 200 {object} models.ItineraryDay
+// @Failure 404 {object} map[string]string "Journée non trouvée"
+// @Security BearerAuth
+// @Router /trips/{id}/days/{day} [get]
+func GetItineraryDay(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleViewer)
+	if !ok {
+		return
+	}
+	day, ok := loadDay(c, trip)
+	if !ok {
+		return
+	}
+
+	if err := database.DB.Where("day_id = ?", day.ID).Order("position, start_time, id").Find(&day.Stops).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des étapes"})
+		return
+	}
+	c.JSON(http.StatusOK, day)
+}
+
+// UpdateItineraryDay godoc
+// @Summary Mettre à jour une journée
+// @Description Met à jour la date, la position, le titre ou les notes d'une journée. Les étapes se gèrent via leurs propres routes.
+// @Tags Itinerary
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Param day path int true "ID de la journée"
+// @Param data body models.ItineraryDay true "Nouvelles données de la journée"
+// @Success 200 {object} models.ItineraryDay
+// @Failure 400 {object} map[string]string "Format invalide"
+// @Failure 404 {object} map[string]string "Journée non trouvée"
+// @Security BearerAuth
+// @Router /trips/{id}/days/{day} [put]
+func UpdateItineraryDay(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleEditor)
+	if !ok {
+		return
+	}
+	day, ok := loadDay(c, trip)
+	if !ok {
+		return
+	}
+
+	id := day.ID
+	if err := c.ShouldBindJSON(day); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Format invalide"})
+		return
+	}
+	day.ID = id
+	day.TripID = trip.ID
+	day.Stops = nil
+
+	if err := validateDay(trip, day); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := database.DB.Save(day).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la mise à jour de la journée"})
+		return
+	}
+	c.JSON(http.StatusOK, day)
+}
+
+// DeleteItineraryDay godoc
+// @Summary Supprimer une journée
+// @Description Supprime une journée du programme ainsi que toutes ses étapes
+// @Tags Itinerary
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Param day path int true "ID de la journée"
+// @Success 200 {object} map[string]string "La journée a bien été supprimée"
+// @Failure 404 {object} map[string]string "Journée non trouvée"
+// @Security BearerAuth
+// @Router /trips/{id}/days/{day} [delete]
+func DeleteItineraryDay(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleEditor)
+	if !ok {
+		return
+	}
+	day, ok := loadDay(c, trip)
+	if !ok {
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("day_id = ?", day.ID).Delete(&models.Stop{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(day).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la suppression"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "La journée a bien été supprimée"})
+}
+
+// GetStops godoc
+// @Summary Étapes d'une journée
+// @Description Retourne les étapes d'une journée, triées par position puis par heure de début
+// @Tags Itinerary
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Param day path int true "ID de la journée"
+// @Success 200 {array} models.Stop
+// @Failure 404 {object} map[string]string "Journée non trouvée"
+// @Security BearerAuth
+// @Router /trips/{id}/days/{day}/stops [get]
+func GetStops(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleViewer)
+	if !ok {
+		return
+	}
+	day, ok := loadDay(c, trip)
+	if !ok {
+		return
+	}
+
+	stops := []models.Stop{}
+	if err := database.DB.Where("day_id = ?", day.ID).Order("position, start_time, id").Find(&stops).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des étapes"})
+		return
+	}
+	c.JSON(http.StatusOK, stops)
+}
+
+// CreateStop godoc
+// @Summary Ajouter une étape
+// @Description Ajoute une étape à une journée du programme
+// @Tags Itinerary
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Param day path int true "ID de la journée"
+// @Param stop body models.Stop true "Étape"
+// @Success 201 {object} models.Stop
+// @Failure 400 {object} map[string]string "Format invalide"
+// @Failure 404 {object} map[string]string "Journée non trouvée"
+// @Security BearerAuth
+// @Router /trips/{id}/days/{day}/stops [post]
+func CreateStop(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleEditor)
+	if !ok {
+		return
+	}
+	day, ok := loadDay(c, trip)
+	if !ok {
+		return
+	}
+
+	var stop models.Stop
+	if err := c.ShouldBindJSON(&stop); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Format invalide"})
+		return
+	}
+	stop.ID = 0
+	stop.DayID = day.ID
+
+	if err := validateStop(&stop); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if stop.Position == 0 {
+		stop.Position = nextPosition(&models.Stop{}, "day_id", day.ID)
+	}
+
+	if err := database.DB.Create(&stop).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la création de l'étape"})
+		return
+	}
+	c.JSON(http.StatusCreated, stop)
+}
+
+// UpdateStop godoc
+// @Summary Mettre à jour une étape
+// @Description Met à jour une étape. Le champ dayId permet de déplacer l'étape vers une autre journée du même voyage.
+// @Tags Itinerary
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Param day path int true "ID de la journée"
+// @Param stop path int true "ID de l'étape"
+// @Param data body models.Stop true "Nouvelles données de l'étape"
+// @Success 200 {object} models.Stop
+// @Failure 400 {object} map[string]string "Format invalide"
+// @Failure 404 {object} map[string]string "Étape non trouvée"
+// @Security BearerAuth
+// @Router /trips/{id}/days/{day}/stops/{stop} [put]
+func UpdateStop(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleEditor)
+	if !ok {
+		return
+	}
+	day, ok := loadDay(c, trip)
+	if !ok {
+		return
+	}
+
+	var stop models.Stop
+	if err := database.DB.Where("day_id = ?", day.ID).First(&stop, paramID(c, "stop")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Étape non trouvée"})
+		return
+	}
+
+	id := stop.ID
+	if err := c.ShouldBindJSON(&stop); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Format invalide"})
+		return
+	}
+	stop.ID = id
+
+	// Déplacement vers une autre journée : elle doit appartenir au même voyage
+	if stop.DayID != day.ID {
+		var count int64
+		database.DB.Model(&models.ItineraryDay{}).Where("id = ? AND trip_id = ?", stop.DayID, trip.ID).Count(&count)
+		if count == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "La journée de destination n'appartient pas à ce voyage"})
+			return
+		}
+	}
+
+	if err := validateStop(&stop); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := database.DB.Save(&stop).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la mise à jour de l'étape"})
+		return
+	}
+	c.JSON(http.StatusOK, stop)
+}
+
+// DeleteStop godoc
+// @Summary Supprimer une étape
+// @Description Supprime une étape d'une journée
+// @Tags Itinerary
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Param day path int true "ID de la journée"
+// @Param stop path int true "ID de l'étape"
+// @Success 200 {object} map[string]string "L'étape a bien été supprimée"
+// @Failure 404 {object} map[string]string "Étape non trouvée"
+// @Security BearerAuth
+// @Router /trips/{id}/days/{day}/stops/{stop} [delete]
+func DeleteStop(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleEditor)
+	if !ok {
+		return
+	}
+	day, ok := loadDay(c, trip)
+	if !ok {
+		return
+	}
+
+	var stop models.Stop
+	if err := database.DB.Where("day_id = ?", day.ID).First(&stop, paramID(c, "stop")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Étape non trouvée"})
+		return
+	}
+
+	if err := database.DB.Delete(&stop).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la suppression"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "L'étape a bien été supprimée"})
+}
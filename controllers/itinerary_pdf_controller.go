@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"travelmate-api/database"
+	"travelmate-api/itinerary"
+	"travelmate-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseOptionalDate lit une date AAAA-MM-JJ ; une date absente ou invalide est nulle
+func parseOptionalDate(value string) time.Time {
+	date, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return date
+}
+
+// itineraryDocument rassemble le voyage, son programme et ses dépenses pour l'impression
+func itineraryDocument(trip *models.Trip) (*itinerary.Document, error) {
+	days, err := loadItinerary(trip.ID)
+	if err != nil {
+		return nil, err
+	}
+	var expenses []models.Expense
+	if err := database.DB.Where("trip_id = ?", trip.ID).Order("date, id").Find(&expenses).Error; err != nil {
+		return nil, err
+	}
+
+	payerIDs := make([]uint, 0, len(expenses))
+	for _, expense := range expenses {
+		payerIDs = append(payerIDs, expense.PayerID)
+	}
+	var payers []models.User
+	if len(payerIDs) > 0 {
+		if err := database.DB.Select("id", "name").Where("id IN ?", payerIDs).Find(&payers).Error; err != nil {
+			return nil, err
+		}
+	}
+	names := map[uint]string{}
+	for _, payer := range payers {
+		names[payer.ID] = payer.Name
+	}
+
+	doc := &itinerary.Document{
+		Title:       trip.Title,
+		Location:    trip.Location,
+		Description: trip.Description,
+		Notes:       trip.Notes,
+		Start:       parseOptionalDate(trip.StartDate),
+		End:         parseOptionalDate(trip.EndDate),
+		HasPosition: hasCoordinates(trip.Latitude, trip.Longitude),
+		Latitude:    trip.Latitude,
+		Longitude:   trip.Longitude,
+	}
+	for _, day := range days {
+		printed := itinerary.Day{Date: parseOptionalDate(day.Date), Title: day.Title, Notes: day.Notes}
+		for _, stop := range day.Stops {
+			printed.Stops = append(printed.Stops, itinerary.Stop{
+				Name:      stop.Name,
+				StartTime: stop.StartTime,
+				EndTime:   stop.EndTime,
+				Notes:     stop.Notes,
+			})
+		}
+		doc.Days = append(doc.Days, printed)
+	}
+	for _, expense := range expenses {
+		doc.Expenses = append(doc.Expenses, itinerary.Expense{
+			Date:     parseOptionalDate(expense.Date),
+			Category: expense.Category,
+			Note:     expense.Note,
+			Payer:    names[expense.PayerID],
+			Amount:   expense.Amount,
+			Currency: expense.Currency,
+		})
+	}
+	return doc, nil
+}
+
+// ExportItineraryPDF godoc
+// @Summary Carnet de voyage imprimable (PDF)
+// @Description Retourne un document PDF A4 reprenant le voyage (titre, dates, lieu, description, notes), un emplacement de carte indiquant sa position, son programme jour par jour et ses dépenses avec leur total par devise. La langue est choisie par le paramètre lang, sinon d'après l'en-tête Accept-Language (français par défaut).
+// @Tags Export
+// @Produce application/pdf
+// @Param id path int true "ID du voyage"
+// @Param lang query string false "Langue du document (fr ou en)"
+// @Success 200 {file} file "Document PDF"
+// @Failure 400 {object} map[string]string "Langue non prise en charge"
+// @Failure 403 {object} map[string]string "Accès refusé"
+// @Failure 404 {object} map[string]string "Voyage non trouvé"
+// @Security BearerAuth
+// @Router /trips/{id}/itinerary.pdf [get]
+func ExportItineraryPDF(c *gin.Context) {
+	locale := itinerary.NegotiateLocale(c.GetHeader("Accept-Language"))
+	if lang := c.Query("lang"); lang != "" {
+		var ok bool
+		if locale, ok = itinerary.LookupLocale(lang); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Langue non prise en charge (fr ou en)"})
+			return
+		}
+	}
+
+	trip, ok := loadTrip(c, models.RoleViewer)
+	if !ok {
+		return
+	}
+	doc, err := itineraryDocument(trip)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération du voyage"})
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := itinerary.WritePDF(&buf, doc, locale); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la génération du PDF"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="itinerary-%d.pdf"`, trip.ID))
+	c.Header("Content-Language", locale.Tag)
+	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
@@ -0,0 +1,21 @@
+package controllers
+
+import (
+	"net/http"
+
+	"travelmate-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetJWKS godoc
+// @Summary Clés publiques de signature des jetons
+// @Description Retourne les clés publiques (JWKS, RFC 7517) permettant de vérifier les jetons émis par l'API, désignées par l'en-tête kid des jetons : la clé courante et les clés remplacées dont les jetons peuvent encore être valides.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Jeu de clés JWKS"
+// @Router /.well-known/jwks.json [get]
+func GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, utils.JWKS())
+}
@@ -0,0 +1,196 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"travelmate-api/database"
+	"travelmate-api/logger"
+	"travelmate-api/models"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// Échecs oubliés après cette durée sans nouvel échec
+const loginFailureWindow = time.Hour
+
+// loginThrottlePolicy définit la tolérance aux échecs de connexion d'un compte ou d'une adresse IP :
+// après FreeAttempts échecs, chaque tentative est retardée (2 s, 4 s, 8 s...), et après
+// MaxFailures échecs, les connexions sont bloquées pendant Lockout
+type loginThrottlePolicy struct {
+	Prefix       string
+	FreeAttempts int
+	MaxFailures  int
+	Lockout      time.Duration
+	AuditEvent   string
+}
+
+func envInt(name string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(name)); err == nil && value > 0 {
+		return value
+	}
+	return fallback
+}
+
+// accountThrottle s'applique à l'adresse email saisie (LOGIN_MAX_FAILURES, 10 par défaut)
+func accountThrottle() loginThrottlePolicy {
+	return loginThrottlePolicy{
+		Prefix:       "account:",
+		FreeAttempts: 3,
+		MaxFailures:  envInt("LOGIN_MAX_FAILURES", 10),
+		Lockout:      time.Duration(envInt("LOGIN_LOCKOUT_MINUTES", 15)) * time.Minute,
+		AuditEvent:   models.AuditLoginLocked,
+	}
+}
+
+// ipThrottle s'applique à l'adresse IP du client (LOGIN_IP_MAX_FAILURES, 50 par défaut)
+func ipThrottle() loginThrottlePolicy {
+	return loginThrottlePolicy{
+		Prefix:       "ip:",
+		FreeAttempts: 10,
+		MaxFailures:  envInt("LOGIN_IP_MAX_FAILURES", 50),
+		Lockout:      time.Duration(envInt("LOGIN_LOCKOUT_MINUTES", 15)) * time.Minute,
+		AuditEvent:   models.AuditLoginIPLocked,
+	}
+}
+
+func accountThrottleKey(email string) string {
+	return accountThrottle().Prefix + strings.ToLower(strings.TrimSpace(email))
+}
+
+// blockedUntil retourne la date avant laquelle une nouvelle tentative est refusée
+func (p loginThrottlePolicy) blockedUntil(throttle *models.LoginThrottle) time.Time {
+	if throttle.LockedUntil != nil && time.Now().Before(*throttle.LockedUntil) {
+		return *throttle.LockedUntil
+	}
+	if throttle.Failures <= p.FreeAttempts {
+		return time.Time{}
+	}
+	// Comparé en secondes : au-delà de 2^33 s, la durée dépasserait un time.Duration
+	backoff := math.Pow(2, float64(throttle.Failures-p.FreeAttempts))
+	if backoff >= p.Lockout.Seconds() {
+		return throttle.LastFailureAt.Add(p.Lockout)
+	}
+	return throttle.LastFailureAt.Add(time.Duration(backoff) * time.Second)
+}
+
+// checkLoginThrottle refuse la tentative (429) si le compte ou l'adresse IP doit encore attendre
+func checkLoginThrottle(c *gin.Context, email string) bool {
+	until := time.Time{}
+	for _, check := range []struct {
+		policy loginThrottlePolicy
+		key    string
+	}{
+		{accountThrottle(), accountThrottleKey(email)},
+		{ipThrottle(), ipThrottle().Prefix + c.ClientIP()},
+	} {
+		var throttle models.LoginThrottle
+		if err := database.DB.Where("key = ?", check.key).First(&throttle).Error; err != nil {
+			continue
+		}
+		if blocked := check.policy.blockedUntil(&throttle); blocked.After(until) {
+			until = blocked
+		}
+	}
+
+	wait := time.Until(until)
+	if wait <= 0 {
+		return true
+	}
+	seconds := int(math.Ceil(wait.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": fmt.Sprintf("Trop de tentatives de connexion, réessayez dans %d secondes", seconds)})
+	return false
+}
+
+// recordFailure compte un échec pour la clé et la verrouille au-delà du nombre d'échecs toléré.
+// Retourne la date de fin du verrouillage s'il vient d'être posé.
+func (p loginThrottlePolicy) recordFailure(key string) (*time.Time, error) {
+	var lockedUntil *time.Time
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var throttle models.LoginThrottle
+		err := tx.Where("key = ?", key).First(&throttle).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		now := time.Now()
+		expired := throttle.LockedUntil != nil && now.After(*throttle.LockedUntil)
+		if expired || now.Sub(throttle.LastFailureAt) > loginFailureWindow {
+			throttle.Failures = 0
+			throttle.LockedUntil = nil
+		}
+		throttle.Key = key
+		throttle.Failures++
+		throttle.LastFailureAt = now
+		if throttle.Failures >= p.MaxFailures && throttle.LockedUntil == nil {
+			until := now.Add(p.Lockout)
+			throttle.LockedUntil = &until
+			lockedUntil = &until
+		}
+		return tx.Save(&throttle).Error
+	})
+	return lockedUntil, err
+}
+
+// recordLoginFailure compte un échec de connexion pour l'adresse email saisie et l'adresse IP,
+// et enregistre un événement d'audit lorsque l'une d'elles est verrouillée
+func recordLoginFailure(c *gin.Context, email string, user *models.User) {
+	ip := c.ClientIP()
+	for _, failure := range []struct {
+		policy loginThrottlePolicy
+		key    string
+	}{
+		{accountThrottle(), accountThrottleKey(email)},
+		{ipThrottle(), ipThrottle().Prefix + ip},
+	} {
+		lockedUntil, err := failure.policy.recordFailure(failure.key)
+		if err != nil {
+			logger.ErrorLogger.Printf("Erreur lors de l'enregistrement d'un échec de connexion (%s) : %v", failure.key, err)
+			continue
+		}
+		if lockedUntil == nil {
+			continue
+		}
+
+		event := models.AuditEvent{
+			Type:    failure.policy.AuditEvent,
+			Email:   email,
+			IP:      ip,
+			Details: fmt.Sprintf("%d échecs de connexion, bloqué jusqu'au %s", failure.policy.MaxFailures, lockedUntil.Format("02/01/2006 à 15:04")),
+		}
+		if user != nil {
+			event.UserID = &user.ID
+		}
+		recordAuditEvent(&event)
+	}
+}
+
+// resetLoginFailures oublie les échecs de connexion du compte après une connexion réussie
+func resetLoginFailures(email string) {
+	database.DB.Where("key = ?", accountThrottleKey(email)).Delete(&models.LoginThrottle{})
+}
+
+// recordAuditEvent enregistre un événement d'audit et le journalise
+func recordAuditEvent(event *models.AuditEvent) {
+	logger.InfoLogger.Printf("AUDIT %s email=%s ip=%s %s", event.Type, event.Email, event.IP, event.Details)
+	if err := database.DB.Create(event).Error; err != nil {
+		logger.ErrorLogger.Printf("Erreur lors de l'enregistrement de l'événement d'audit %s : %v", event.Type, err)
+	}
+}
+
+// dummyPasswordHash sert à comparer un mot de passe quand l'email est inconnu, pour que la
+// durée de la réponse ne révèle pas si le compte existe
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("travelmate-dummy-password"), bcrypt.DefaultCost)
+	return hash
+})
@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"travelmate-api/database"
+	"travelmate-api/models"
+)
+
+func testThrottlePolicy() loginThrottlePolicy {
+	return loginThrottlePolicy{Prefix: "test:", FreeAttempts: 3, MaxFailures: 5, Lockout: 15 * time.Minute}
+}
+
+func TestBlockedUntil(t *testing.T) {
+	last := time.Now().Add(-time.Second)
+	future := time.Now().Add(10 * time.Minute)
+	past := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name     string
+		throttle models.LoginThrottle
+		want     time.Time
+	}{
+		{name: "no failure", want: time.Time{}},
+		{name: "free attempts", throttle: models.LoginThrottle{Failures: 3, LastFailureAt: last}, want: time.Time{}},
+		{name: "first delayed attempt", throttle: models.LoginThrottle{Failures: 4, LastFailureAt: last}, want: last.Add(2 * time.Second)},
+		{name: "exponential backoff", throttle: models.LoginThrottle{Failures: 6, LastFailureAt: last}, want: last.Add(8 * time.Second)},
+		{name: "backoff capped at the lockout", throttle: models.LoginThrottle{Failures: 40, LastFailureAt: last}, want: last.Add(15 * time.Minute)},
+		{name: "backoff beyond int64 nanoseconds (2^34 s)", throttle: models.LoginThrottle{Failures: 37, LastFailureAt: last}, want: last.Add(15 * time.Minute)},
+		{name: "backoff beyond int64 nanoseconds (2^40 s)", throttle: models.LoginThrottle{Failures: 43, LastFailureAt: last}, want: last.Add(15 * time.Minute)},
+		{name: "locked", throttle: models.LoginThrottle{Failures: 5, LastFailureAt: last, LockedUntil: &future}, want: future},
+		{name: "expired lock falls back to the backoff", throttle: models.LoginThrottle{Failures: 5, LastFailureAt: last, LockedUntil: &past}, want: last.Add(4 * time.Second)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := testThrottlePolicy().blockedUntil(&tt.throttle); !got.Equal(tt.want) {
+				t.Errorf("blockedUntil() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordFailure(t *testing.T) {
+	future := time.Now().Add(10 * time.Minute)
+	past := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name         string
+		existing     *models.LoginThrottle
+		wantFailures int
+		wantLock     bool
+		wantLocked   bool
+	}{
+		{name: "first failure", wantFailures: 1},
+		{name: "below the maximum", existing: &models.LoginThrottle{Failures: 3, LastFailureAt: time.Now()}, wantFailures: 4},
+		{name: "reaching the maximum locks", existing: &models.LoginThrottle{Failures: 4, LastFailureAt: time.Now()}, wantFailures: 5, wantLock: true, wantLocked: true},
+		{name: "already locked", existing: &models.LoginThrottle{Failures: 5, LastFailureAt: time.Now(), LockedUntil: &future}, wantFailures: 6, wantLocked: true},
+		{name: "expired lock starts over", existing: &models.LoginThrottle{Failures: 5, LastFailureAt: past, LockedUntil: &past}, wantFailures: 1},
+		{name: "old failures are forgotten", existing: &models.LoginThrottle{Failures: 4, LastFailureAt: time.Now().Add(-2 * loginFailureWindow)}, wantFailures: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			policy := testThrottlePolicy()
+			key := policy.Prefix + "alice@example.com"
+			if tt.existing != nil {
+				tt.existing.Key = key
+				if err := database.DB.Create(tt.existing).Error; err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			lockedUntil, err := policy.recordFailure(key)
+			if err != nil {
+				t.Fatalf("recordFailure() error = %v", err)
+			}
+			if (lockedUntil != nil) != tt.wantLock {
+				t.Errorf("recordFailure() lock = %v, want lock %v", lockedUntil, tt.wantLock)
+			}
+			if lockedUntil != nil && time.Until(*lockedUntil) < policy.Lockout-time.Minute {
+				t.Errorf("locked until %v, want about %v from now", lockedUntil, policy.Lockout)
+			}
+
+			var throttle models.LoginThrottle
+			if err := database.DB.Where("key = ?", key).First(&throttle).Error; err != nil {
+				t.Fatal(err)
+			}
+			if throttle.Failures != tt.wantFailures {
+				t.Errorf("failures = %d, want %d", throttle.Failures, tt.wantFailures)
+			}
+			if locked := throttle.LockedUntil != nil && time.Now().Before(*throttle.LockedUntil); locked != tt.wantLocked {
+				t.Errorf("locked = %v, want %v (%+v)", locked, tt.wantLocked, throttle)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"travelmate-api/database"
+	"travelmate-api/logger"
+	"travelmate-api/models"
+	"travelmate-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	logger.InfoLogger = log.New(io.Discard, "", 0)
+	logger.ErrorLogger = log.New(io.Discard, "", 0)
+
+	keysDir, err := os.MkdirTemp("", "travelmate-keys")
+	if err != nil {
+		log.Fatal(err)
+	}
+	os.Setenv("JWT_KEYS_DIR", keysDir)
+	if err := utils.InitKeys(); err != nil {
+		log.Fatal(err)
+	}
+
+	code := m.Run()
+	os.RemoveAll(keysDir)
+	os.Exit(code)
+}
+
+// setupTestDB remplace la base par une base SQLite vide, propre au test
+func setupTestDB(t *testing.T) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{Logger: gormlogger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.User{}, &models.Session{}, &models.RefreshToken{}, &models.OIDCLogin{}, &models.UserIdentity{}, &models.LoginThrottle{}, &models.AuditEvent{}); err != nil {
+		t.Fatal(err)
+	}
+
+	previous := database.DB
+	database.DB = db
+	t.Cleanup(func() {
+		database.DB = previous
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+}
@@ -0,0 +1,203 @@
+package controllers
+
+import (
+	"net/http"
+
+	"travelmate-api/database"
+	"travelmate-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// loadMember récupère le membre :user du voyage donné
+func loadMember(c *gin.Context, trip *models.Trip) (*models.TripMember, bool) {
+	var member models.TripMember
+	if err := database.DB.Where("trip_id = ? AND user_id = ?", trip.ID, paramID(c, "user")).First(&member).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Membre non trouvé"})
+		return nil, false
+	}
+	return &member, true
+}
+
+// GetTripMembers godoc
+// @Summary Membres d'un voyage
+// @Description Retourne les utilisateurs ayant accès au voyage et leur rôle (owner, editor ou viewer)
+// @Tags Members
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Success 200 {array} models.TripMember
+// @Failure 403 {object} map[string]string "Accès refusé"
+// @Failure 404 {object} map[string]string "Voyage non trouvé"
+// @Security BearerAuth
+// @Router /trips/{id}/members [get]
+func GetTripMembers(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleViewer)
+	if !ok {
+		return
+	}
+
+	members := []models.TripMember{}
+	err := database.DB.
+		Preload("User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "name", "email")
+		}).
+		Where("trip_id = ?", trip.ID).
+		Order("id").
+		Find(&members).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des membres"})
+		return
+	}
+	c.JSON(http.StatusOK, members)
+}
+
+// AddTripMember godoc
+// @Summary Ajouter un membre
+// @Description Donne accès au voyage à un utilisateur, désigné par son ID ou son email. Réservé aux propriétaires du voyage.
+// @Tags Members
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Param member body object{userId=uint,email=string,role=string} true "Utilisateur et rôle (viewer par défaut)"
+// @Success 201 {object} models.TripMember
+// @Failure 400 {object} map[string]string "Format invalide"
+// @Failure 403 {object} map[string]string "Accès refusé"
+// @Failure 404 {object} map[string]string "Utilisateur non trouvé"
+// @Failure 409 {object} map[string]string "L'utilisateur est déjà membre du voyage"
+// @Security BearerAuth
+// @Router /trips/{id}/members [post]
+func AddTripMember(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleOwner)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		UserID uint   `json:"userId"`
+		Email  string `json:"email" binding:"omitempty,email"`
+		Role   string `json:"role"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil || (input.UserID == 0 && input.Email == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Format invalide : userId ou email requis"})
+		return
+	}
+	if input.Role == "" {
+		input.Role = models.RoleViewer
+	}
+	if models.RoleRank(input.Role) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Rôle invalide (owner, editor ou viewer)"})
+		return
+	}
+
+	var user models.User
+	query := database.DB.Select("id", "name", "email")
+	if input.UserID != 0 {
+		query = query.Where("id = ?", input.UserID)
+	} else {
+		query = query.Where("email = ?", input.Email)
+	}
+	if err := query.First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Utilisateur non trouvé"})
+		return
+	}
+
+	var count int64
+	database.DB.Model(&models.TripMember{}).Where("trip_id = ? AND user_id = ?", trip.ID, user.ID).Count(&count)
+	if count > 0 || user.ID == trip.UserID {
+		c.JSON(http.StatusConflict, gin.H{"error": "L'utilisateur est déjà membre du voyage"})
+		return
+	}
+
+	member := models.TripMember{TripID: trip.ID, UserID: user.ID, Role: input.Role}
+	if err := database.DB.Omit("User").Create(&member).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de l'ajout du membre"})
+		return
+	}
+	member.User = &user
+	c.JSON(http.StatusCreated, member)
+}
+
+// UpdateTripMember godoc
+// @Summary Changer le rôle d'un membre
+// @Description Modifie le rôle d'un membre du voyage. Réservé aux propriétaires ; le créateur du voyage reste propriétaire.
+// @Tags Members
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Param user path int true "ID de l'utilisateur"
+// @Param role body object{role=string} true "Nouveau rôle"
+// @Success 200 {object} models.TripMember
+// @Failure 400 {object} map[string]string "Rôle invalide"
+// @Failure 403 {object} map[string]string "Accès refusé"
+// @Failure 404 {object} map[string]string "Membre non trouvé"
+// @Security BearerAuth
+// @Router /trips/{id}/members/{user} [put]
+func UpdateTripMember(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleOwner)
+	if !ok {
+		return
+	}
+	member, ok := loadMember(c, trip)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Role string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil || models.RoleRank(input.Role) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Rôle invalide (owner, editor ou viewer)"})
+		return
+	}
+	if member.UserID == trip.UserID && input.Role != models.RoleOwner {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Le créateur du voyage reste propriétaire"})
+		return
+	}
+
+	member.Role = input.Role
+	if err := database.DB.Omit("User").Save(member).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la mise à jour du membre"})
+		return
+	}
+	c.JSON(http.StatusOK, member)
+}
+
+// RemoveTripMember godoc
+// @Summary Retirer un membre
+// @Description Retire l'accès d'un utilisateur au voyage. Réservé aux propriétaires, sauf pour quitter soi-même le voyage.
+// @Tags Members
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Param user path int true "ID de l'utilisateur"
+// @Success 200 {object} map[string]string "Le membre a bien été retiré"
+// @Failure 403 {object} map[string]string "Accès refusé"
+// @Failure 404 {object} map[string]string "Membre non trouvé"
+// @Security BearerAuth
+// @Router /trips/{id}/members/{user} [delete]
+func RemoveTripMember(c *gin.Context) {
+	// Un membre peut toujours quitter le voyage
+	role := models.RoleOwner
+	if paramID(c, "user") == c.GetUint("user_id") {
+		role = models.RoleViewer
+	}
+
+	trip, ok := loadTrip(c, role)
+	if !ok {
+		return
+	}
+	member, ok := loadMember(c, trip)
+	if !ok {
+		return
+	}
+	if member.UserID == trip.UserID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Le créateur du voyage ne peut pas être retiré"})
+		return
+	}
+
+	if err := database.DB.Delete(member).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la suppression"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Le membre a bien été retiré"})
+}
@@ -0,0 +1,267 @@
+package controllers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"travelmate-api/database"
+	"travelmate-api/logger"
+	"travelmate-api/models"
+	"travelmate-api/sso"
+	"travelmate-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	// Durée laissée pour se connecter chez le fournisseur d'identité
+	oidcLoginTTL = 10 * time.Minute
+	// Cookie reliant une connexion en cours au navigateur qui l'a commencée
+	oidcLoginCookie = "tm_oidc_login"
+	oidcCookiePath  = "/auth/oidc/"
+)
+
+var (
+	// errOIDCLoginInvalid signale un état de connexion inconnu, expiré ou déjà utilisé
+	errOIDCLoginInvalid = errors.New("oidc login invalid")
+	// errOIDCEmailUnverified signale une identité sans adresse email vérifiée par le fournisseur
+	errOIDCEmailUnverified = errors.New("oidc email unverified")
+	// errOIDCAccountUnverified signale un compte existant dont l'adresse email n'est pas vérifiée :
+	// le relier permettrait à quiconque l'a créé de garder l'accès au compte
+	errOIDCAccountUnverified = errors.New("oidc account email unverified")
+)
+
+// lookupProvider retourne le fournisseur d'identité :provider, ou répond 404
+func lookupProvider(c *gin.Context) (*sso.Provider, bool) {
+	provider, err := sso.Lookup(c.Param("provider"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Fournisseur d'identité inconnu"})
+		return nil, false
+	}
+	return provider, true
+}
+
+// setOIDCLoginCookie place (ou efface, si value est vide) le cookie de connexion en cours
+func setOIDCLoginCookie(c *gin.Context, value string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcLoginCookie, value, maxAge, oidcCookiePath, "", strings.HasPrefix(requestBaseURL(c), "https://"), true)
+}
+
+// consumeOIDCLogin retrouve la connexion en cours correspondant à l'état retourné par le
+// fournisseur, vérifie qu'elle a été commencée par ce navigateur et la supprime, pour
+// qu'elle ne serve qu'une fois
+func consumeOIDCLogin(providerName, state, browser string) (*models.OIDCLogin, error) {
+	var oidcLogin models.OIDCLogin
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("state_hash = ? AND provider = ?", utils.HashToken(state), providerName).First(&oidcLogin).Error; err != nil {
+			return errOIDCLoginInvalid
+		}
+		// Un code et un état interceptés (Referer, journaux...) ne servent à rien sans le cookie
+		if browser == "" || subtle.ConstantTimeCompare([]byte(utils.HashToken(browser)), []byte(oidcLogin.BrowserHash)) != 1 {
+			return errOIDCLoginInvalid
+		}
+		result := tx.Delete(&oidcLogin)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 || time.Now().After(oidcLogin.ExpiresAt) {
+			return errOIDCLoginInvalid
+		}
+		return nil
+	})
+	return &oidcLogin, err
+}
+
+// oidcUser retourne le compte relié à l'identité. Une identité inconnue est reliée au compte
+// de même adresse email, ou à un nouveau compte, à condition que le fournisseur ait vérifié
+// cette adresse.
+func oidcUser(providerName string, claims *sso.Claims) (*models.User, error) {
+	var user models.User
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var identity models.UserIdentity
+		err := tx.Where("provider = ? AND subject = ?", providerName, claims.Subject).First(&identity).Error
+		if err == nil {
+			if err := tx.Model(&identity).Updates(map[string]interface{}{"email": claims.Email, "last_login_at": time.Now()}).Error; err != nil {
+				return err
+			}
+			return tx.First(&user, identity.UserID).Error
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if claims.Email == "" || !claims.EmailVerified {
+			return errOIDCEmailUnverified
+		}
+		err = tx.Where("LOWER(email) = LOWER(?)", claims.Email).First(&user).Error
+		switch {
+		case err == nil:
+			if !user.EmailVerified {
+				return errOIDCAccountUnverified
+			}
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			// Le mot de passe aléatoire n'est connu de personne : il peut être défini par
+			// « Mot de passe oublié »
+			password, err := utils.GenerateRandomToken()
+			if err != nil {
+				return err
+			}
+			hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+			if err != nil {
+				return err
+			}
+			name := claims.Name
+			if name == "" {
+				name, _, _ = strings.Cut(claims.Email, "@")
+			}
+			user = models.User{Name: name, Email: claims.Email, Password: string(hashedPassword), EmailVerified: true}
+			if err := tx.Create(&user).Error; err != nil {
+				return err
+			}
+		default:
+			return err
+		}
+
+		return tx.Create(&models.UserIdentity{
+			UserID:      user.ID,
+			Provider:    providerName,
+			Subject:     claims.Subject,
+			Email:       claims.Email,
+			LastLoginAt: time.Now(),
+		}).Error
+	})
+	return &user, err
+}
+
+// OIDCLogin godoc
+// @Summary Connexion par un fournisseur d'identité
+// @Description Redirige vers la page de connexion du fournisseur d'identité OpenID Connect (code d'autorisation avec PKCE), et place un cookie tm_oidc_login reliant la connexion au navigateur. Après connexion, le fournisseur redirige vers /auth/oidc/{provider}/callback, ou vers l'adresse de retour configurée pour le fournisseur (OIDC_<NOM>_REDIRECT_URL), qui doit alors transmettre les paramètres code et state à cette route.
+// @Tags auth
+// @Param provider path string true "Nom du fournisseur (OIDC_PROVIDERS)"
+// @Success 302 "Redirection vers le fournisseur d'identité"
+// @Failure 404 {object} map[string]string "Fournisseur d'identité inconnu"
+// @Failure 502 {object} map[string]string "Fournisseur d'identité indisponible"
+// @Router /auth/oidc/{provider}/login [get]
+func OIDCLogin(c *gin.Context) {
+	provider, ok := lookupProvider(c)
+	if !ok {
+		return
+	}
+
+	var secrets [4]string
+	for i := range secrets {
+		secret, err := utils.GenerateRandomToken()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la préparation de la connexion"})
+			return
+		}
+		secrets[i] = secret
+	}
+	state, nonce, verifier, browser := secrets[0], secrets[1], secrets[2], secrets[3]
+
+	redirectURL := requestBaseURL(c) + "/auth/oidc/" + provider.Name + "/callback"
+	authURL, err := provider.AuthCodeURL(c.Request.Context(), redirectURL, state, nonce, verifier)
+	if err != nil {
+		logger.ErrorLogger.Printf("Fournisseur d'identité %s indisponible : %v", provider.Name, err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Fournisseur d'identité indisponible"})
+		return
+	}
+
+	database.DB.Where("expires_at < ?", time.Now()).Delete(&models.OIDCLogin{})
+	oidcLogin := models.OIDCLogin{
+		Provider:     provider.Name,
+		StateHash:    utils.HashToken(state),
+		BrowserHash:  utils.HashToken(browser),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		RedirectURL:  redirectURL,
+		ExpiresAt:    time.Now().Add(oidcLoginTTL),
+	}
+	if err := database.DB.Create(&oidcLogin).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la préparation de la connexion"})
+		return
+	}
+
+	setOIDCLoginCookie(c, browser, int(oidcLoginTTL.Seconds()))
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// OIDCCallback godoc
+// @Summary Retour du fournisseur d'identité
+// @Description Termine la connexion par un fournisseur d'identité, dans le navigateur qui l'a commencée (cookie tm_oidc_login) : vérifie le jeton d'identité et retourne les jetons d'accès et de rafraîchissement. Une identité inconnue est reliée au compte de même adresse email, ou à un nouveau compte, si le fournisseur a vérifié cette adresse. Si la double authentification est active, retourne un jeton de connexion à utiliser avec POST /auth/2fa/verify.
+// @Tags auth
+// @Produce json
+// @Param provider path string true "Nom du fournisseur (OIDC_PROVIDERS)"
+// @Param code query string true "Code d'autorisation"
+// @Param state query string true "État de la connexion"
+// @Success 200 {object} models.TokenPair "Connexion établie"
+// @Success 202 {object} models.TwoFactorChallenge "Double authentification requise"
+// @Failure 400 {object} map[string]string "Connexion invalide, expirée ou commencée dans un autre navigateur"
+// @Failure 401 {object} map[string]string "Connexion refusée par le fournisseur d'identité"
+// @Failure 403 {object} map[string]string "Adresse email non vérifiée par le fournisseur"
+// @Failure 404 {object} map[string]string "Fournisseur d'identité inconnu"
+// @Failure 409 {object} map[string]string "Compte existant dont l'adresse email n'est pas vérifiée"
+// @Router /auth/oidc/{provider}/callback [get]
+func OIDCCallback(c *gin.Context) {
+	provider, ok := lookupProvider(c)
+	if !ok {
+		return
+	}
+	if c.Query("error") != "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Connexion refusée par le fournisseur d'identité"})
+		return
+	}
+	code, state := c.Query("code"), c.Query("state")
+	if code == "" || state == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Les paramètres code et state sont requis"})
+		return
+	}
+
+	browser, _ := c.Cookie(oidcLoginCookie)
+	setOIDCLoginCookie(c, "", -1)
+	oidcLogin, err := consumeOIDCLogin(provider.Name, state, browser)
+	if errors.Is(err, errOIDCLoginInvalid) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Connexion invalide ou expirée, recommencez"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la connexion"})
+		return
+	}
+
+	claims, err := provider.Exchange(c.Request.Context(), oidcLogin.RedirectURL, code, oidcLogin.Nonce, oidcLogin.CodeVerifier)
+	if err != nil {
+		logger.ErrorLogger.Printf("Échec de la connexion par le fournisseur d'identité %s : %v", provider.Name, err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Connexion refusée par le fournisseur d'identité"})
+		return
+	}
+
+	user, err := oidcUser(provider.Name, claims)
+	switch {
+	case errors.Is(err, errOIDCEmailUnverified):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Le fournisseur d'identité n'a pas vérifié votre adresse email"})
+		return
+	case errors.Is(err, errOIDCAccountUnverified):
+		c.JSON(http.StatusConflict, gin.H{"error": "Un compte existe déjà avec cette adresse email : vérifiez-la depuis ce compte avant de vous connecter avec ce fournisseur"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la connexion"})
+		return
+	}
+
+	if user.TwoFactorEnabled {
+		twoFactorChallenge(c, user)
+		return
+	}
+	tokens, err := login(c, user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la génération du token"})
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
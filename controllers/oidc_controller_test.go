@@ -0,0 +1,331 @@
+package controllers
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"travelmate-api/database"
+	"travelmate-api/models"
+	"travelmate-api/sso"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-jose/go-jose/v4"
+)
+
+const (
+	testClientID     = "travelmate"
+	testClientSecret = "s3cret"
+)
+
+// testAuthorization est une connexion acceptée par le fournisseur de test, en attente de
+// l'échange de son code
+type testAuthorization struct {
+	nonce     string
+	challenge string
+	claims    map[string]any
+}
+
+// testIssuer est un fournisseur d'identité OpenID Connect de test : découverte, JWKS et
+// échange du code, qui vérifie le code_verifier PKCE et signe le jeton d'identité
+type testIssuer struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mu        sync.Mutex
+	codes     map[string]testAuthorization
+	verifiers []string
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuer := &testIssuer{key: key, codes: map[string]testAuthorization{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                                issuer.URL,
+			"authorization_endpoint":                issuer.URL + "/authorize",
+			"token_endpoint":                        issuer.URL + "/token",
+			"jwks_uri":                              issuer.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &key.PublicKey, KeyID: "test", Algorithm: "RS256", Use: "sig"},
+		}})
+	})
+	mux.HandleFunc("/token", issuer.token)
+	issuer.Server = httptest.NewServer(mux)
+	t.Cleanup(issuer.Close)
+
+	t.Setenv("OIDC_PROVIDERS", "test")
+	t.Setenv("OIDC_TEST_ISSUER", issuer.URL)
+	t.Setenv("OIDC_TEST_CLIENT_ID", testClientID)
+	t.Setenv("OIDC_TEST_CLIENT_SECRET", testClientSecret)
+	sso.InitProviders()
+	return issuer
+}
+
+// authorize simule la connexion de l'utilisateur chez le fournisseur et retourne le code
+// d'autorisation ; claims complète ou remplace les informations du jeton d'identité
+func (i *testIssuer) authorize(t *testing.T, authURL string, claims map[string]any) string {
+	t.Helper()
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := parsed.Query()
+	if query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		t.Fatalf("authorization request without PKCE S256: %s", authURL)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	code := "code-" + strconv.Itoa(len(i.codes)+1)
+	i.codes[code] = testAuthorization{nonce: query.Get("nonce"), challenge: query.Get("code_challenge"), claims: claims}
+	return code
+}
+
+func (i *testIssuer) token(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	i.mu.Lock()
+	authorization, ok := i.codes[r.Form.Get("code")]
+	delete(i.codes, r.Form.Get("code"))
+	i.verifiers = append(i.verifiers, r.Form.Get("code_verifier"))
+	i.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+	clientID, clientSecret, _ := r.BasicAuth()
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != authorization.challenge || clientID != testClientID || clientSecret != testClientSecret {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	claims := map[string]any{
+		"iss":            i.URL,
+		"aud":            testClientID,
+		"sub":            "alice-sub",
+		"email":          "alice@example.com",
+		"email_verified": true,
+		"name":           "Alice Martin",
+		"nonce":          authorization.nonce,
+		"iat":            time.Now().Unix(),
+		"exp":            time.Now().Add(time.Hour).Unix(),
+	}
+	for name, value := range authorization.claims {
+		claims[name] = value
+	}
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: jose.JSONWebKey{Key: i.key, KeyID: "test"}}, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	payload, _ := json.Marshal(claims)
+	signed, err := signer.Sign(payload)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	idToken, _ := signed.CompactSerialize()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"access_token": "access", "token_type": "Bearer", "expires_in": 3600, "id_token": idToken})
+}
+
+func oidcRouter() *gin.Engine {
+	r := gin.New()
+	r.GET("/auth/oidc/:provider/login", OIDCLogin)
+	r.GET("/auth/oidc/:provider/callback", OIDCCallback)
+	return r
+}
+
+// startOIDCLogin lance une connexion et retourne l'adresse de connexion du fournisseur et
+// le cookie qui relie la connexion au navigateur
+func startOIDCLogin(t *testing.T, r *gin.Engine) (string, *http.Cookie) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/oidc/test/login", nil))
+	if w.Code != http.StatusFound {
+		t.Fatalf("login: status %d, body %s", w.Code, w.Body)
+	}
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == oidcLoginCookie {
+			if !cookie.HttpOnly || cookie.SameSite != http.SameSiteLaxMode || cookie.Value == "" {
+				t.Fatalf("login cookie must be HttpOnly and SameSite=Lax: %+v", cookie)
+			}
+			return w.Header().Get("Location"), cookie
+		}
+	}
+	t.Fatalf("login: no %s cookie", oidcLoginCookie)
+	return "", nil
+}
+
+// oidcCallback simule le retour du fournisseur, avec le cookie de connexion s'il est donné
+func oidcCallback(r *gin.Engine, code, state string, cookie *http.Cookie) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	query := url.Values{"code": {code}, "state": {state}}
+	req := httptest.NewRequest(http.MethodGet, "/auth/oidc/test/callback?"+query.Encode(), nil)
+	if cookie != nil {
+		req.AddCookie(&http.Cookie{Name: cookie.Name, Value: cookie.Value})
+	}
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func stateOf(t *testing.T, authURL string) string {
+	t.Helper()
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed.Query().Get("state")
+}
+
+func TestOIDCCallback(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing *models.User
+		claims   map[string]any
+		status   int
+	}{
+		{name: "new account", status: http.StatusOK},
+		{name: "nonce mismatch", claims: map[string]any{"nonce": "another-nonce"}, status: http.StatusUnauthorized},
+		{name: "email not verified by the provider", claims: map[string]any{"email_verified": false}, status: http.StatusForbidden},
+		{name: "existing verified account", existing: &models.User{Name: "Alice", Email: "Alice@Example.com", EmailVerified: true}, status: http.StatusOK},
+		{name: "existing unverified account", existing: &models.User{Name: "Alice", Email: "alice@example.com"}, status: http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			issuer := newTestIssuer(t)
+			r := oidcRouter()
+			if tt.existing != nil {
+				tt.existing.Password = "unused"
+				if err := database.DB.Create(tt.existing).Error; err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			authURL, cookie := startOIDCLogin(t, r)
+			w := oidcCallback(r, issuer.authorize(t, authURL, tt.claims), stateOf(t, authURL), cookie)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.status, w.Body)
+			}
+
+			var identities []models.UserIdentity
+			database.DB.Find(&identities)
+			if tt.status != http.StatusOK {
+				if len(identities) != 0 {
+					t.Fatalf("identity linked despite status %d: %+v", w.Code, identities)
+				}
+				return
+			}
+			if len(identities) != 1 || identities[0].Subject != "alice-sub" {
+				t.Fatalf("identities = %+v, want one for alice-sub", identities)
+			}
+			if tt.existing != nil && identities[0].UserID != tt.existing.ID {
+				t.Fatalf("identity linked to user %d, want existing user %d", identities[0].UserID, tt.existing.ID)
+			}
+			var users int64
+			database.DB.Model(&models.User{}).Count(&users)
+			if users != 1 {
+				t.Fatalf("%d users, want 1", users)
+			}
+		})
+	}
+}
+
+func TestOIDCCallbackSendsPKCEVerifier(t *testing.T) {
+	setupTestDB(t)
+	issuer := newTestIssuer(t)
+	r := oidcRouter()
+
+	authURL, cookie := startOIDCLogin(t, r)
+	if w := oidcCallback(r, issuer.authorize(t, authURL, nil), stateOf(t, authURL), cookie); w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body)
+	}
+
+	var pending int64
+	database.DB.Model(&models.OIDCLogin{}).Count(&pending)
+	if pending != 0 {
+		t.Fatalf("%d login states kept after the callback", pending)
+	}
+	parsed, _ := url.Parse(authURL)
+	sum := sha256.Sum256([]byte(issuer.verifiers[0]))
+	if issuer.verifiers[0] == "" || base64.RawURLEncoding.EncodeToString(sum[:]) != parsed.Query().Get("code_challenge") {
+		t.Fatalf("code_verifier %q does not match code_challenge %q", issuer.verifiers[0], parsed.Query().Get("code_challenge"))
+	}
+}
+
+func TestOIDCCallbackRejectsReplayedState(t *testing.T) {
+	setupTestDB(t)
+	issuer := newTestIssuer(t)
+	r := oidcRouter()
+
+	authURL, cookie := startOIDCLogin(t, r)
+	state := stateOf(t, authURL)
+	if w := oidcCallback(r, issuer.authorize(t, authURL, nil), state, cookie); w.Code != http.StatusOK {
+		t.Fatalf("first callback: status = %d, body %s", w.Code, w.Body)
+	}
+	if w := oidcCallback(r, issuer.authorize(t, authURL, nil), state, cookie); w.Code != http.StatusBadRequest {
+		t.Fatalf("replayed state: status = %d, want %d (body %s)", w.Code, http.StatusBadRequest, w.Body)
+	}
+	if len(issuer.verifiers) != 1 {
+		t.Fatalf("replayed state reached the token endpoint (%d exchanges)", len(issuer.verifiers))
+	}
+}
+
+func TestOIDCCallbackRequiresLoginCookie(t *testing.T) {
+	setupTestDB(t)
+	issuer := newTestIssuer(t)
+	r := oidcRouter()
+
+	authURL, cookie := startOIDCLogin(t, r)
+	state := stateOf(t, authURL)
+	_, otherBrowser := startOIDCLogin(t, r)
+	for _, tt := range []struct {
+		name   string
+		cookie *http.Cookie
+	}{
+		{name: "without the cookie", cookie: nil},
+		{name: "with the cookie of another login", cookie: otherBrowser},
+	} {
+		w := oidcCallback(r, issuer.authorize(t, authURL, nil), state, tt.cookie)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: status = %d, want %d (body %s)", tt.name, w.Code, http.StatusBadRequest, w.Body)
+		}
+	}
+	if len(issuer.verifiers) != 0 {
+		t.Fatalf("callback without the login cookie reached the token endpoint (%d exchanges)", len(issuer.verifiers))
+	}
+
+	// La connexion reste utilisable par le navigateur qui l'a commencée, et le cookie est effacé
+	w := oidcCallback(r, issuer.authorize(t, authURL, nil), state, cookie)
+	if w.Code != http.StatusOK {
+		t.Fatalf("callback with the login cookie: status = %d, body %s", w.Code, w.Body)
+	}
+	cleared := false
+	for _, c := range w.Result().Cookies() {
+		cleared = cleared || (c.Name == oidcLoginCookie && c.MaxAge < 0)
+	}
+	if !cleared {
+		t.Fatalf("login cookie not cleared after the callback: %v", w.Result().Cookies())
+	}
+}
@@ -0,0 +1,232 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"travelmate-api/database"
+	"travelmate-api/logger"
+	"travelmate-api/mailer"
+	"travelmate-api/models"
+	"travelmate-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	// Délai minimal entre deux demandes de réinitialisation pour une même adresse email ou une même adresse IP
+	passwordResetRequestDelay = time.Minute
+	// Nombre de demandes de réinitialisation en attente au-delà duquel les suivantes sont refusées
+	passwordResetQueueSize = 100
+)
+
+// errResetTokenInvalid signale un jeton de réinitialisation inconnu, expiré ou déjà utilisé
+var errResetTokenInvalid = errors.New("reset token invalid")
+
+// passwordResetTTL retourne la durée de validité d'un lien de réinitialisation (PASSWORD_RESET_TTL_MINUTES, 1 heure par défaut)
+func passwordResetTTL() time.Duration {
+	if minutes, err := strconv.Atoi(os.Getenv("PASSWORD_RESET_TTL_MINUTES")); err == nil && minutes > 0 {
+		return time.Duration(minutes) * time.Minute
+	}
+	return time.Hour
+}
+
+// sendPasswordReset crée un jeton de réinitialisation pour le compte associé à l'email,
+// s'il existe, et l'envoie par email. Les demandes précédentes sont annulées.
+func sendPasswordReset(email string) {
+	var user models.User
+	if err := database.DB.Where("LOWER(email) = ?", email).First(&user).Error; err != nil {
+		return
+	}
+
+	token, err := utils.GenerateRandomToken()
+	if err != nil {
+		logger.ErrorLogger.Println("Erreur lors de la génération du jeton de réinitialisation :", err)
+		return
+	}
+	reset := models.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(passwordResetTTL()),
+	}
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND used_at IS NULL", user.ID).Delete(&models.PasswordReset{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&reset).Error
+	})
+	if err != nil {
+		logger.ErrorLogger.Println("Erreur lors de l'enregistrement de la demande de réinitialisation :", err)
+		return
+	}
+
+	body := fmt.Sprintf(
+		"Bonjour %s,\n\nUne réinitialisation du mot de passe de votre compte TravelMate a été demandée.\n\n"+
+			"Pour choisir un nouveau mot de passe, ouvrez le lien suivant :\n%s/reset-password?token=%s\n\n"+
+			"Ce lien ne peut être utilisé qu'une fois et expire le %s.\n"+
+			"Si vous n'êtes pas à l'origine de cette demande, ignorez cet email : votre mot de passe reste inchangé.",
+		user.Name, appURL(), token, reset.ExpiresAt.Format("02/01/2006 à 15:04"),
+	)
+	err = mailer.Send(mailer.Message{To: user.Email, Subject: "Réinitialisation de votre mot de passe", Body: body})
+	if err != nil {
+		logger.ErrorLogger.Printf("Erreur lors de l'envoi de l'email de réinitialisation (user_id=%d) : %v", user.ID, err)
+	}
+}
+
+// passwordResetRequests retient la date de la dernière demande de réinitialisation par adresse
+// email saisie et par adresse IP
+var passwordResetRequests = struct {
+	sync.Mutex
+	last map[string]time.Time
+}{last: map[string]time.Time{}}
+
+// reservePasswordReset enregistre une demande de réinitialisation pour les clés, ou retourne le
+// temps d'attente si l'une d'elles a fait une demande il y a moins de passwordResetRequestDelay
+func reservePasswordReset(keys ...string) time.Duration {
+	passwordResetRequests.Lock()
+	defer passwordResetRequests.Unlock()
+
+	now := time.Now()
+	for key, last := range passwordResetRequests.last {
+		if now.Sub(last) >= passwordResetRequestDelay {
+			delete(passwordResetRequests.last, key)
+		}
+	}
+
+	var wait time.Duration
+	for _, key := range keys {
+		if last, ok := passwordResetRequests.last[key]; ok {
+			wait = max(wait, passwordResetRequestDelay-now.Sub(last))
+		}
+	}
+	if wait > 0 {
+		return wait
+	}
+	for _, key := range keys {
+		passwordResetRequests.last[key] = now
+	}
+	return 0
+}
+
+// passwordResetQueue transmet les demandes de réinitialisation à un unique worker, qui les
+// traite l'une après l'autre
+var passwordResetQueue = sync.OnceValue(func() chan<- string {
+	queue := make(chan string, passwordResetQueueSize)
+	go func() {
+		for email := range queue {
+			sendPasswordReset(email)
+		}
+	}()
+	return queue
+})
+
+// ForgotPassword godoc
+// @Summary Mot de passe oublié
+// @Description Envoie par email un lien de réinitialisation du mot de passe, à usage unique et limité dans le temps. La réponse est la même que l'adresse corresponde ou non à un compte. Une seule demande par minute est traitée pour une même adresse email ou une même adresse IP : les suivantes sont ignorées, avec la même réponse.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body object{email=string} true "Email du compte"
+// @Success 202 {object} map[string]string "Demande prise en compte"
+// @Failure 400 {object} map[string]string "Email manquant ou invalide"
+// @Router /auth/forgot-password [post]
+func ForgotPassword(c *gin.Context) {
+	var input struct {
+		Email string `json:"email" form:"email" binding:"required,email"`
+	}
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Format invalide : email requis"})
+		return
+	}
+
+	// La réponse est toujours la même : une demande limitée ou refusée ne doit pas se distinguer
+	// d'une demande traitée, ni révéler qu'une réinitialisation a déjà été demandée pour l'adresse
+	email := strings.ToLower(strings.TrimSpace(input.Email))
+	if wait := reservePasswordReset("email:"+email, "ip:"+c.ClientIP()); wait > 0 {
+		logger.InfoLogger.Printf("Demande de réinitialisation ignorée (trop rapprochée) ip=%s", c.ClientIP())
+	} else {
+		// Le traitement a lieu après la réponse : son temps d'exécution ne révèle pas si le compte existe
+		select {
+		case passwordResetQueue() <- email:
+		default:
+			logger.ErrorLogger.Println("Trop de demandes de réinitialisation en attente : demande ignorée")
+		}
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Si un compte correspond à cette adresse, un email de réinitialisation a été envoyé"})
+}
+
+// ResetPassword godoc
+// @Summary Réinitialiser le mot de passe
+// @Description Définit un nouveau mot de passe à l'aide du jeton reçu par email. Le jeton ne peut plus être réutilisé et toutes les sessions de l'utilisateur sont révoquées.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body object{token=string,password=string} true "Jeton reçu par email et nouveau mot de passe (6 caractères minimum)"
+// @Success 200 {object} map[string]string "Mot de passe modifié"
+// @Failure 400 {object} map[string]string "Champs invalides, ou lien invalide ou expiré"
+// @Router /auth/reset-password [post]
+func ResetPassword(c *gin.Context) {
+	var input struct {
+		Token    string `json:"token" form:"token" binding:"required"`
+		Password string `json:"password" form:"password" binding:"required,min=6"`
+	}
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Format invalide : jeton et mot de passe (6 caractères minimum) requis"})
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors du hash du mot de passe"})
+		return
+	}
+
+	var reset models.PasswordReset
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("token_hash = ?", utils.HashToken(input.Token)).First(&reset).Error; err != nil {
+			return errResetTokenInvalid
+		}
+		if reset.UsedAt != nil || time.Now().After(reset.ExpiresAt) {
+			return errResetTokenInvalid
+		}
+		// La condition sur used_at garantit un usage unique, même en cas de requêtes simultanées
+		result := tx.Model(&models.PasswordReset{}).Where("id = ? AND used_at IS NULL", reset.ID).Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errResetTokenInvalid
+		}
+		return tx.Model(&models.User{}).Where("id = ?", reset.UserID).Update("password", string(hashedPassword)).Error
+	})
+	if errors.Is(err, errResetTokenInvalid) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Lien de réinitialisation invalide ou expiré"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la modification du mot de passe"})
+		return
+	}
+
+	if _, err := revokeSessions(database.DB.Where("user_id = ?", reset.UserID)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la révocation des sessions"})
+		return
+	}
+
+	// Le lien reçu par email prouve que la demande vient du titulaire du compte : il est déverrouillé
+	var user models.User
+	if err := database.DB.Select("id", "email").First(&user, reset.UserID).Error; err == nil {
+		resetLoginFailures(user.Email)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Mot de passe modifié : reconnectez-vous avec le nouveau mot de passe"})
+}
@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"travelmate-api/database"
+	"travelmate-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// listSessions retourne les sessions actives (ni révoquées ni expirées) d'un utilisateur,
+// la plus récemment utilisée en premier
+func listSessions(c *gin.Context, userID uint) {
+	sessions := []models.Session{}
+	err := database.DB.
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC, id DESC").
+		Find(&sessions).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des sessions"})
+		return
+	}
+
+	current := c.GetUint("session_id")
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == current
+	}
+	c.JSON(http.StatusOK, sessions)
+}
+
+// revokeSession révoque la session :session d'un utilisateur
+func revokeSession(c *gin.Context, userID uint) {
+	revoked, err := revokeSessions(database.DB.Where("id = ? AND user_id = ?", paramID(c, "session"), userID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la révocation de la session"})
+		return
+	}
+	if revoked == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session non trouvée"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "La session a bien été révoquée"})
+}
+
+// GetMySessions godoc
+// @Summary Mes sessions
+// @Description Retourne les appareils sur lesquels l'utilisateur est connecté : navigateur (User-Agent), adresse IP, date de connexion et de dernière activité. La session utilisée pour la requête est marquée current.
+// @Tags Sessions
+// @Produce json
+// @Success 200 {array} models.Session
+// @Failure 401 {object} map[string]string "Non authentifié"
+// @Security BearerAuth
+// @Router /me/sessions [get]
+func GetMySessions(c *gin.Context) {
+	listSessions(c, c.GetUint("user_id"))
+}
+
+// DeleteMySession godoc
+// @Summary Révoquer une de mes sessions
+// @Description Déconnecte un appareil (téléphone perdu...) : ses jetons d'accès et de rafraîchissement sont refusés immédiatement.
+// @Tags Sessions
+// @Produce json
+// @Param session path int true "ID de la session"
+// @Success 200 {object} map[string]string "La session a bien été révoquée"
+// @Failure 404 {object} map[string]string "Session non trouvée"
+// @Security BearerAuth
+// @Router /me/sessions/{session} [delete]
+func DeleteMySession(c *gin.Context) {
+	revokeSession(c, c.GetUint("user_id"))
+}
+
+// GetUserSessions godoc
+// @Summary Sessions d'un utilisateur (admin)
+// @Description Retourne les sessions actives d'un utilisateur. Réservé aux administrateurs.
+// @Tags Admin
+// @Produce json
+// @Param id path int true "ID de l'utilisateur"
+// @Success 200 {array} models.Session
+// @Failure 403 {object} map[string]string "Accès réservé aux administrateurs"
+// @Security BearerAuth
+// @Router /admin/users/{id}/sessions [get]
+func GetUserSessions(c *gin.Context) {
+	listSessions(c, paramID(c, "id"))
+}
+
+// DeleteUserSession godoc
+// @Summary Révoquer une session d'un utilisateur (admin)
+// @Description Déconnecte un appareil d'un utilisateur. Réservé aux administrateurs.
+// @Tags Admin
+// @Produce json
+// @Param id path int true "ID de l'utilisateur"
+// @Param session path int true "ID de la session"
+// @Success 200 {object} map[string]string "La session a bien été révoquée"
+// @Failure 403 {object} map[string]string "Accès réservé aux administrateurs"
+// @Failure 404 {object} map[string]string "Session non trouvée"
+// @Security BearerAuth
+// @Router /admin/users/{id}/sessions/{session} [delete]
+func DeleteUserSession(c *gin.Context) {
+	revokeSession(c, paramID(c, "id"))
+}
+
+// DeleteUserSessions godoc
+// @Summary Révoquer toutes les sessions d'un utilisateur (admin)
+// @Description Déconnecte l'utilisateur de tous ses appareils. Réservé aux administrateurs.
+// @Tags Admin
+// @Produce json
+// @Param id path int true "ID de l'utilisateur"
+// @Success 200 {object} map[string]interface{} "Nombre de sessions révoquées"
+// @Failure 403 {object} map[string]string "Accès réservé aux administrateurs"
+// @Security BearerAuth
+// @Router /admin/users/{id}/sessions [delete]
+func DeleteUserSessions(c *gin.Context) {
+	revoked, err := revokeSessions(database.DB.Where("user_id = ?", paramID(c, "id")))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la révocation des sessions"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Les sessions ont bien été révoquées", "revoked": revoked})
+}
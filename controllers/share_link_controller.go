@@ -0,0 +1,276 @@
+package controllers
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"travelmate-api/database"
+	"travelmate-api/models"
+	"travelmate-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestBaseURL retourne l'adresse publique de l'API : API_URL si elle est configurée, sinon
+// l'adresse reconstruite à partir de la requête. Les en-têtes X-Forwarded-Proto et
+// X-Forwarded-Host ne sont lus que si la connexion vient d'un proxy listé dans TRUSTED_PROXIES :
+// n'importe quel client pourrait sinon choisir l'adresse des liens retournés par l'API
+func requestBaseURL(c *gin.Context) string {
+	if url := os.Getenv("API_URL"); url != "" {
+		return strings.TrimRight(url, "/")
+	}
+
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	host := c.Request.Host
+	if fromTrustedProxy(c) {
+		if proto := strings.ToLower(strings.TrimSpace(strings.Split(c.GetHeader("X-Forwarded-Proto"), ",")[0])); proto == "http" || proto == "https" {
+			scheme = proto
+		}
+		if forwarded := strings.TrimSpace(strings.Split(c.GetHeader("X-Forwarded-Host"), ",")[0]); forwarded != "" {
+			host = forwarded
+		}
+	}
+	return scheme + "://" + host
+}
+
+// trustedProxies retourne les réseaux listés dans TRUSTED_PROXIES (IP ou CIDR séparés par des
+// virgules), comme pour la détection de l'adresse IP du client
+var trustedProxies = sync.OnceValue(func() []*net.IPNet {
+	var networks []*net.IPNet
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip != nil && ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+		if _, network, err := net.ParseCIDR(proxy); err == nil {
+			networks = append(networks, network)
+		}
+	}
+	return networks
+})
+
+// fromTrustedProxy indique si la connexion vient d'un des proxys de TRUSTED_PROXIES
+func fromTrustedProxy(c *gin.Context) bool {
+	ip := net.ParseIP(c.RemoteIP())
+	if ip == nil {
+		return false
+	}
+	for _, network := range trustedProxies() {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizeTrip construit la vue publique d'un voyage selon les options du lien
+func sanitizeTrip(trip *models.Trip, days []models.ItineraryDay, link *models.ShareLink) models.SharedTrip {
+	shared := models.SharedTrip{
+		Title:       trip.Title,
+		Description: trip.Description,
+		Location:    trip.Location,
+		StartDate:   trip.StartDate,
+		EndDate:     trip.EndDate,
+		Longitude:   trip.Longitude,
+		Latitude:    trip.Latitude,
+		Days:        []models.SharedDay{},
+	}
+	if link.IncludeNotes {
+		shared.Notes = trip.Notes
+	}
+	if link.IncludeOwner {
+		var owner models.User
+		if err := database.DB.Select("id", "name").First(&owner, trip.UserID).Error; err == nil {
+			shared.UserID = owner.ID
+			shared.OwnerName = owner.Name
+		}
+	}
+
+	for _, day := range days {
+		sharedDay := models.SharedDay{Date: day.Date, Title: day.Title, Stops: []models.SharedStop{}}
+		if link.IncludeNotes {
+			sharedDay.Notes = day.Notes
+		}
+		for _, stop := range day.Stops {
+			sharedStop := models.SharedStop{
+				Name:      stop.Name,
+				StartTime: stop.StartTime,
+				EndTime:   stop.EndTime,
+				Latitude:  stop.Latitude,
+				Longitude: stop.Longitude,
+			}
+			if link.IncludeNotes {
+				sharedStop.Notes = stop.Notes
+			}
+			sharedDay.Stops = append(sharedDay.Stops, sharedStop)
+		}
+		shared.Days = append(shared.Days, sharedDay)
+	}
+	return shared
+}
+
+// CreateShareLink godoc
+// @Summary Créer un lien de partage
+// @Description Crée un lien public en lecture seule vers le voyage, révocable et éventuellement limité dans le temps. Le jeton n'est retourné qu'une seule fois. Réservé aux propriétaires du voyage.
+// @Tags Sharing
+// @Accept json
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Param options body object{expiresAt=string,includeNotes=bool,includeOwner=bool} false "Options du lien"
+// @Success 201 {object} models.ShareLink
+// @Failure 400 {object} map[string]string "Format invalide"
+// @Failure 403 {object} map[string]string "Accès refusé"
+// @Security BearerAuth
+// @Router /trips/{id}/share-links [post]
+func CreateShareLink(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleOwner)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		ExpiresAt    *time.Time `json:"expiresAt"`
+		IncludeNotes bool       `json:"includeNotes"`
+		IncludeOwner bool       `json:"includeOwner"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format invalide"})
+			return
+		}
+	}
+	if input.ExpiresAt != nil && input.ExpiresAt.Before(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "La date d'expiration doit être dans le futur"})
+		return
+	}
+
+	token, err := utils.GenerateRandomToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la génération du lien"})
+		return
+	}
+
+	link := models.ShareLink{
+		TripID:       trip.ID,
+		TokenHash:    utils.HashToken(token),
+		CreatedByID:  c.GetUint("user_id"),
+		IncludeNotes: input.IncludeNotes,
+		IncludeOwner: input.IncludeOwner,
+		ExpiresAt:    input.ExpiresAt,
+	}
+	if err := database.DB.Create(&link).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la création du lien"})
+		return
+	}
+
+	link.Token = token
+	link.URL = requestBaseURL(c) + "/shared/" + token
+	c.JSON(http.StatusCreated, link)
+}
+
+// GetShareLinks godoc
+// @Summary Liens de partage d'un voyage
+// @Description Retourne les liens de partage du voyage (sans leur jeton). Réservé aux propriétaires du voyage.
+// @Tags Sharing
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Success 200 {array} models.ShareLink
+// @Failure 403 {object} map[string]string "Accès refusé"
+// @Security BearerAuth
+// @Router /trips/{id}/share-links [get]
+func GetShareLinks(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleOwner)
+	if !ok {
+		return
+	}
+
+	links := []models.ShareLink{}
+	if err := database.DB.Where("trip_id = ?", trip.ID).Order("created_at DESC").Find(&links).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des liens"})
+		return
+	}
+	c.JSON(http.StatusOK, links)
+}
+
+// RevokeShareLink godoc
+// @Summary Révoquer un lien de partage
+// @Description Désactive définitivement un lien de partage. Réservé aux propriétaires du voyage.
+// @Tags Sharing
+// @Produce json
+// @Param id path int true "ID du voyage"
+// @Param link path int true "ID du lien"
+// @Success 200 {object} map[string]string "Le lien a bien été révoqué"
+// @Failure 404 {object} map[string]string "Lien non trouvé"
+// @Security BearerAuth
+// @Router /trips/{id}/share-links/{link} [delete]
+func RevokeShareLink(c *gin.Context) {
+	trip, ok := loadTrip(c, models.RoleOwner)
+	if !ok {
+		return
+	}
+
+	var link models.ShareLink
+	if err := database.DB.Where("trip_id = ?", trip.ID).First(&link, paramID(c, "link")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Lien non trouvé"})
+		return
+	}
+
+	if link.RevokedAt == nil {
+		if err := database.DB.Model(&link).Update("revoked_at", time.Now()).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la révocation du lien"})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Le lien a bien été révoqué"})
+}
+
+// GetSharedTrip godoc
+// @Summary Consulter un voyage partagé
+// @Description Retourne la vue publique d'un voyage à partir d'un lien de partage, sans authentification. Les notes et l'identité du propriétaire ne sont visibles que si le lien le prévoit.
+// @Tags Sharing
+// @Produce json
+// @Param token path string true "Jeton du lien de partage"
+// @Success 200 {object} models.SharedTrip
+// @Failure 404 {object} map[string]string "Lien invalide, expiré ou révoqué"
+// @Router /shared/{token} [get]
+func GetSharedTrip(c *gin.Context) {
+	// Même réponse quelle que soit la raison, pour ne rien révéler sur les liens existants
+	notFound := gin.H{"error": "Lien invalide, expiré ou révoqué"}
+
+	var link models.ShareLink
+	if err := database.DB.Where("token_hash = ?", utils.HashToken(c.Param("token"))).First(&link).Error; err != nil {
+		c.JSON(http.StatusNotFound, notFound)
+		return
+	}
+	if link.RevokedAt != nil || (link.ExpiresAt != nil && time.Now().After(*link.ExpiresAt)) {
+		c.JSON(http.StatusNotFound, notFound)
+		return
+	}
+
+	var trip models.Trip
+	if err := database.DB.First(&trip, link.TripID).Error; err != nil {
+		c.JSON(http.StatusNotFound, notFound)
+		return
+	}
+	days, err := loadItinerary(trip.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération du programme"})
+		return
+	}
+
+	c.JSON(http.StatusOK, sanitizeTrip(&trip, days, &link))
+}
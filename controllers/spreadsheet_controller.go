@@ -0,0 +1,407 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"travelmate-api/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+)
+
+// Formats de tableur
+const (
+	formatCSV  = "csv"
+	formatXLSX = "xlsx"
+)
+
+// Modes d'import : tout ou rien, ou seulement les lignes valides
+const (
+	importAtomic  = "atomic"
+	importPartial = "partial"
+)
+
+const spreadsheetName = "Voyages"
+
+// tripColumns sont les colonnes des fichiers exportés, qui servent aussi de noms de champs à l'import
+var tripColumns = []string{"title", "description", "location", "startDate", "endDate", "latitude", "longitude", "notes"}
+
+// normalizeColumn simplifie un nom de colonne pour la correspondance : casse, espaces, - et _ ignorés
+func normalizeColumn(name string) string {
+	return strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.ToLower(strings.TrimSpace(name)))
+}
+
+// Limites de décompression d'un classeur importé, pour qu'un petit fichier ne puisse pas
+// occuper toute la mémoire une fois décompressé
+const (
+	xlsxUnzipSizeLimit    = 50 << 20
+	xlsxUnzipXMLSizeLimit = 10 << 20
+)
+
+// escapeFormula neutralise un texte qu'un tableur interpréterait comme une formule (commençant
+// par =, +, -, @, une tabulation ou un retour chariot) en le préfixant d'une apostrophe
+func escapeFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// unescapeFormula retire l'apostrophe ajoutée par escapeFormula à l'export
+func unescapeFormula(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune("=+-@\t\r", rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+// tripRow retourne les valeurs d'un voyage dans l'ordre de tripColumns. Les textes saisis par
+// les membres sont neutralisés pour ne pas être exécutés comme formules à l'ouverture du fichier.
+func tripRow(trip *models.Trip) []string {
+	return []string{
+		escapeFormula(trip.Title),
+		escapeFormula(trip.Description),
+		escapeFormula(trip.Location),
+		escapeFormula(trip.StartDate),
+		escapeFormula(trip.EndDate),
+		strconv.FormatFloat(trip.Latitude, 'f', -1, 64),
+		strconv.FormatFloat(trip.Longitude, 'f', -1, 64),
+		escapeFormula(trip.Notes),
+	}
+}
+
+// parseSheetDate accepte les dates AAAA-MM-JJ, JJ/MM/AAAA et les numéros de série des dates Excel
+func parseSheetDate(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+	for _, layout := range []string{dateLayout, "02/01/2006", "2006/01/02"} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date.Format(dateLayout), nil
+		}
+	}
+	if serial, err := strconv.ParseFloat(value, 64); err == nil {
+		if date, err := excelize.ExcelDateToTime(serial, false); err == nil {
+			return date.Format(dateLayout), nil
+		}
+	}
+	return "", fmt.Errorf("Date invalide : %s", value)
+}
+
+// parseSheetNumber accepte les nombres à virgule ou à point décimal
+func parseSheetNumber(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	number, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+	if err != nil {
+		return 0, fmt.Errorf("Nombre invalide : %s", value)
+	}
+	return number, nil
+}
+
+// setTripField affecte la valeur d'une cellule au champ correspondant du voyage
+func setTripField(trip *models.Trip, field, value string) error {
+	var err error
+	value = unescapeFormula(value)
+	switch field {
+	case "title":
+		trip.Title = strings.TrimSpace(value)
+	case "description":
+		trip.Description = value
+	case "location":
+		trip.Location = strings.TrimSpace(value)
+	case "startDate":
+		trip.StartDate, err = parseSheetDate(value)
+	case "endDate":
+		trip.EndDate, err = parseSheetDate(value)
+	case "latitude":
+		trip.Latitude, err = parseSheetNumber(value)
+	case "longitude":
+		trip.Longitude, err = parseSheetNumber(value)
+	case "notes":
+		trip.Notes = value
+	}
+	return err
+}
+
+// columnFields associe chaque colonne du fichier à un champ du voyage, d'après la
+// correspondance fournie (nom de colonne → champ) ou à défaut d'après le nom de la colonne.
+// Les colonnes inconnues sont ignorées.
+func columnFields(header []string, mapping map[string]string) ([]string, error) {
+	known := map[string]string{}
+	for _, column := range tripColumns {
+		known[normalizeColumn(column)] = column
+	}
+
+	normalizedMapping := map[string]string{}
+	for column, field := range mapping {
+		target, ok := known[normalizeColumn(field)]
+		if !ok {
+			return nil, fmt.Errorf("Champ inconnu dans la correspondance des colonnes : %s", field)
+		}
+		normalizedMapping[normalizeColumn(column)] = target
+	}
+
+	fields := make([]string, len(header))
+	hasTitle := false
+	for i, column := range header {
+		if len(mapping) > 0 {
+			fields[i] = normalizedMapping[normalizeColumn(column)]
+		} else {
+			fields[i] = known[normalizeColumn(column)]
+		}
+		hasTitle = hasTitle || fields[i] == "title"
+	}
+	if !hasTitle {
+		return nil, errors.New("Aucune colonne ne correspond au titre du voyage (title)")
+	}
+	return fields, nil
+}
+
+// readCSV lit toutes les lignes d'un fichier CSV séparé par des virgules ou des points-virgules
+func readCSV(r io.Reader) ([][]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+
+	// Le séparateur est celui qui apparaît le plus dans la première ligne
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	reader := csv.NewReader(bytes.NewReader(data))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	return reader.ReadAll()
+}
+
+// readXLSX lit les lignes de la première feuille d'un classeur, sans mise en forme des
+// cellules : les dates sont lues comme des numéros de série
+func readXLSX(r io.Reader) ([][]string, error) {
+	book, err := excelize.OpenReader(r, excelize.Options{
+		UnzipSizeLimit:    xlsxUnzipSizeLimit,
+		UnzipXMLSizeLimit: xlsxUnzipXMLSizeLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer book.Close()
+
+	sheets := book.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, errors.New("Le classeur ne contient aucune feuille")
+	}
+	return book.GetRows(sheets[0], excelize.Options{RawCellValue: true})
+}
+
+// ExportTripsSpreadsheet godoc
+// @Summary Exporter les voyages en CSV ou XLSX
+// @Description Retourne les voyages visibles par l'utilisateur dans un fichier CSV (UTF-8) ou un classeur XLSX, une ligne par voyage. Les colonnes (title, description, location, startDate, endDate, latitude, longitude, notes) sont celles attendues par l'import. Les filtres de la liste des voyages s'appliquent.
+// @Tags Export
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "Format du fichier (csv par défaut, ou xlsx)"
+// @Param userId query int false "ID de l'utilisateur (administrateurs uniquement)"
+// @Param all query bool false "Tous les voyages (administrateurs uniquement)"
+// @Param from query string false "Voyages se terminant à partir de cette date (AAAA-MM-JJ)"
+// @Param to query string false "Voyages commençant au plus tard à cette date (AAAA-MM-JJ)"
+// @Param location query string false "Localisation (correspondance partielle)"
+// @Param status query string false "Statut du voyage (upcoming, ongoing ou past)"
+// @Success 200 {string} string "Fichier CSV ou XLSX"
+// @Failure 400 {object} map[string]string "Paramètre invalide"
+// @Failure 403 {object} map[string]string "Accès refusé"
+// @Security BearerAuth
+// @Router /trips/export [get]
+func ExportTripsSpreadsheet(c *gin.Context) {
+	format := c.DefaultQuery("format", formatCSV)
+	if format != formatCSV && format != formatXLSX {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le paramètre 'format' doit valoir csv ou xlsx"})
+		return
+	}
+
+	query, ok := scopedTrips(c, c.Query("userId"))
+	if !ok {
+		return
+	}
+	opts, err := parseListOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var trips []models.Trip
+	if err := opts.filter(query).Order("trips.start_date, trips.id").Find(&trips).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des voyages"})
+		return
+	}
+
+	var buf bytes.Buffer
+	contentType := "text/csv; charset=utf-8"
+	if format == formatCSV {
+		// L'indicateur d'ordre des octets permet à Excel de reconnaître l'UTF-8
+		buf.WriteString("\ufeff")
+		writer := csv.NewWriter(&buf)
+		writer.Write(tripColumns)
+		for i := range trips {
+			writer.Write(tripRow(&trips[i]))
+		}
+		writer.Flush()
+		err = writer.Error()
+	} else {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		err = writeTripsXLSX(&buf, trips)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de l'export"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="voyages.%s"`, format))
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// writeTripsXLSX écrit les voyages dans un classeur d'une feuille
+func writeTripsXLSX(w io.Writer, trips []models.Trip) error {
+	book := excelize.NewFile()
+	defer book.Close()
+	if err := book.SetSheetName(book.GetSheetName(0), spreadsheetName); err != nil {
+		return err
+	}
+
+	header := make([]interface{}, len(tripColumns))
+	for i, column := range tripColumns {
+		header[i] = column
+	}
+	if err := book.SetSheetRow(spreadsheetName, "A1", &header); err != nil {
+		return err
+	}
+
+	for i := range trips {
+		values := tripRow(&trips[i])
+		row := make([]interface{}, len(values))
+		for j, value := range values {
+			row[j] = value
+		}
+		// Les coordonnées sont écrites comme des nombres
+		row[5], row[6] = trips[i].Latitude, trips[i].Longitude
+
+		cell, err := excelize.CoordinatesToCellName(1, i+2)
+		if err != nil {
+			return err
+		}
+		if err := book.SetSheetRow(spreadsheetName, cell, &row); err != nil {
+			return err
+		}
+	}
+	return book.Write(w)
+}
+
+// ImportTripsSpreadsheet godoc
+// @Summary Importer des voyages depuis un fichier CSV ou XLSX
+// @Description Crée un voyage par ligne du fichier (la première ligne contient les noms des colonnes). Les colonnes sont associées aux champs du voyage par leur nom, ou selon la correspondance fournie dans mapping (objet JSON nom de colonne → champ). Les dates sont acceptées aux formats AAAA-MM-JJ et JJ/MM/AAAA. Chaque ligne invalide est signalée avec son numéro et la raison. En mode atomic (par défaut), rien n'est créé si une ligne est invalide ; en mode partial, seules les lignes valides sont importées. Les doublons (même titre et mêmes dates qu'un voyage existant) ne sont pas créés.
+// @Tags Import
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Fichier .csv ou .xlsx (5 Mo au maximum)"
+// @Param mapping formData string false "Correspondance des colonnes, par exemple {\"Nom\":\"title\",\"Début\":\"startDate\"}"
+// @Param format query string false "Format du fichier (csv ou xlsx), déduit de l'extension par défaut"
+// @Param mode query string false "atomic (par défaut) ou partial"
+// @Param dryRun query bool false "Simulation, sans création"
+// @Success 200 {object} models.ImportReport "Simulation, ou aucun voyage créé"
+// @Success 201 {object} models.ImportReport "Voyages créés"
+// @Failure 400 {object} map[string]string "Fichier manquant ou invalide"
+// @Failure 413 {object} map[string]string "Fichier trop volumineux"
+// @Failure 422 {object} models.ImportReport "Lignes invalides en mode atomic, rien n'a été créé"
+// @Security BearerAuth
+// @Router /trips/import [post]
+func ImportTripsSpreadsheet(c *gin.Context) {
+	mode := c.DefaultQuery("mode", importAtomic)
+	if mode != importAtomic && mode != importPartial {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le paramètre 'mode' doit valoir atomic ou partial"})
+		return
+	}
+
+	header, ok := importFile(c)
+	if !ok {
+		return
+	}
+
+	format := c.Query("format")
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), ".")
+	}
+	if format != formatCSV && format != formatXLSX {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le fichier doit être au format csv ou xlsx"})
+		return
+	}
+
+	var mapping map[string]string
+	if value := c.PostForm("mapping"); value != "" {
+		if err := json.Unmarshal([]byte(value), &mapping); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "La correspondance des colonnes doit être un objet JSON"})
+			return
+		}
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Fichier illisible"})
+		return
+	}
+	defer file.Close()
+
+	var rows [][]string
+	if format == formatCSV {
+		rows, err = readCSV(file)
+	} else {
+		rows, err = readXLSX(file)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Fichier illisible : " + err.Error()})
+		return
+	}
+	if len(rows) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le fichier est vide"})
+		return
+	}
+
+	fields, err := columnFields(rows[0], mapping)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Les numéros de ligne sont ceux du fichier : la première ligne de données est la ligne 2
+	candidates := []importCandidate{}
+	for i, row := range rows[1:] {
+		if strings.TrimSpace(strings.Join(row, "")) == "" {
+			continue
+		}
+		candidate := importCandidate{Row: i + 2, Trip: &models.Trip{}}
+		for j, value := range row {
+			if j >= len(fields) || fields[j] == "" {
+				continue
+			}
+			if err := setTripField(candidate.Trip, fields[j], value); err != nil {
+				candidate.Err = err
+				break
+			}
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	runImport(c, candidates, c.Query("dryRun") == "true", mode == importAtomic)
+}
@@ -0,0 +1,240 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"travelmate-api/database"
+	"travelmate-api/logger"
+	"travelmate-api/models"
+	"travelmate-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// errRefreshTokenReused signale un jeton de rafraîchissement déjà échangé
+var errRefreshTokenReused = errors.New("refresh token reused")
+
+// startSession ouvre une session pour l'appareil à l'origine de la requête
+func startSession(tx *gorm.DB, c *gin.Context, user *models.User) (*models.Session, error) {
+	now := time.Now()
+	session := models.Session{
+		UserID:     user.ID,
+		UserAgent:  c.Request.UserAgent(),
+		IP:         c.ClientIP(),
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(utils.RefreshTokenTTL()),
+	}
+	return &session, tx.Create(&session).Error
+}
+
+// login ouvre une session et retourne ses premiers jetons
+func login(c *gin.Context, user *models.User) (*models.TokenPair, error) {
+	var tokens *models.TokenPair
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		session, err := startSession(tx, c, user)
+		if err != nil {
+			return err
+		}
+		tokens, err = issueTokens(tx, user, session)
+		return err
+	})
+	return tokens, err
+}
+
+// issueTokens crée un jeton de rafraîchissement pour la session, dont il prolonge la durée
+// de vie, et le jeton d'accès associé
+func issueTokens(tx *gorm.DB, user *models.User, session *models.Session) (*models.TokenPair, error) {
+	refresh, err := utils.GenerateRandomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(utils.RefreshTokenTTL())
+	record := models.RefreshToken{
+		UserID:    user.ID,
+		SessionID: session.ID,
+		TokenHash: utils.HashToken(refresh),
+		ExpiresAt: expiresAt,
+	}
+	if err := tx.Create(&record).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Model(session).Update("expires_at", expiresAt).Error; err != nil {
+		return nil, err
+	}
+
+	access, err := utils.GenerateJWT(user.ID, user.Name, user.IsAdmin, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.TokenPair{
+		Token:        access,
+		RefreshToken: refresh,
+		ExpiresIn:    int(utils.AccessTokenTTL().Seconds()),
+	}, nil
+}
+
+// revokeSessions révoque les sessions actives correspondant à la requête et leurs jetons de
+// rafraîchissement. Les jetons d'accès de ces sessions sont refusés dès la requête suivante.
+func revokeSessions(query *gorm.DB) (int64, error) {
+	var ids []uint
+	if err := query.Model(&models.Session{}).Where("revoked_at IS NULL").Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Session{}).Where("id IN ?", ids).Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.RefreshToken{}).Where("session_id IN ? AND revoked_at IS NULL", ids).Update("revoked_at", now).Error
+	})
+	return int64(len(ids)), err
+}
+
+// revokeAccessToken ajoute le jeton d'accès de la requête à la liste des jetons révoqués,
+// jusqu'à son expiration, et purge les entrées expirées
+func revokeAccessToken(c *gin.Context) error {
+	expiresAt, ok := c.Get("token_expires_at")
+	if !ok {
+		expiresAt = time.Now().Add(utils.AccessTokenTTL())
+	}
+	if err := database.DB.Where("expires_at < ?", time.Now()).Delete(&models.RevokedToken{}).Error; err != nil {
+		return err
+	}
+	return database.DB.Create(&models.RevokedToken{JTI: c.GetString("jti"), ExpiresAt: expiresAt.(time.Time)}).Error
+}
+
+// RefreshToken godoc
+// @Summary Renouveler le jeton d'accès
+// @Description Échange un jeton de rafraîchissement contre un nouveau jeton d'accès et un nouveau jeton de rafraîchissement ; l'ancien ne peut plus être utilisé. Présenter un jeton déjà échangé révoque toute la session, qui a pu être compromise.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body object{refreshToken=string} true "Jeton de rafraîchissement"
+// @Success 200 {object} models.TokenPair
+// @Failure 400 {object} map[string]string "Jeton manquant"
+// @Failure 401 {object} map[string]string "Jeton invalide, expiré, révoqué ou réutilisé"
+// @Router /auth/refresh [post]
+func RefreshToken(c *gin.Context) {
+	var input struct {
+		RefreshToken string `json:"refreshToken" form:"refreshToken" binding:"required"`
+	}
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le jeton de rafraîchissement est requis"})
+		return
+	}
+
+	var record models.RefreshToken
+	if err := database.DB.Where("token_hash = ?", utils.HashToken(input.RefreshToken)).First(&record).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Jeton de rafraîchissement invalide"})
+		return
+	}
+	if record.RevokedAt != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Jeton de rafraîchissement révoqué"})
+		return
+	}
+	if record.UsedAt == nil && time.Now().After(record.ExpiresAt) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Jeton de rafraîchissement expiré"})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, record.UserID).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Utilisateur non trouvé"})
+		return
+	}
+	var session models.Session
+	if err := database.DB.First(&session, record.SessionID).Error; err != nil || session.RevokedAt != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session expirée ou révoquée"})
+		return
+	}
+
+	var tokens *models.TokenPair
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if record.UsedAt != nil {
+			return errRefreshTokenReused
+		}
+		// La condition sur used_at garantit qu'un jeton n'est échangé qu'une fois, même en cas de requêtes simultanées
+		result := tx.Model(&models.RefreshToken{}).Where("id = ? AND used_at IS NULL", record.ID).Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errRefreshTokenReused
+		}
+		var err error
+		if err := tx.Model(&session).Updates(map[string]interface{}{"last_seen_at": time.Now(), "ip": c.ClientIP()}).Error; err != nil {
+			return err
+		}
+		tokens, err = issueTokens(tx, &user, &session)
+		return err
+	})
+
+	if errors.Is(err, errRefreshTokenReused) {
+		if _, err := revokeSessions(database.DB.Where("id = ?", session.ID)); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la révocation des jetons"})
+			return
+		}
+		logger.ErrorLogger.Printf("Réutilisation d'un jeton de rafraîchissement : session révoquée (user_id=%d, session_id=%d)", user.ID, session.ID)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Jeton de rafraîchissement déjà utilisé : la connexion a été révoquée"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la génération du token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Logout godoc
+// @Summary Déconnexion
+// @Description Révoque immédiatement le jeton d'accès utilisé et la session dont il est issu, avec ses jetons de rafraîchissement. Les autres appareils restent connectés.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} map[string]string "Déconnexion effectuée"
+// @Failure 401 {object} map[string]string "Non authentifié"
+// @Security BearerAuth
+// @Router /auth/logout [post]
+func Logout(c *gin.Context) {
+	query := database.DB.Where("id = ? AND user_id = ?", c.GetUint("session_id"), c.GetUint("user_id"))
+	if _, err := revokeSessions(query); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la révocation des jetons"})
+		return
+	}
+	if err := revokeAccessToken(c); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la révocation du token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Déconnexion effectuée"})
+}
+
+// LogoutAll godoc
+// @Summary Déconnexion de tous les appareils
+// @Description Révoque toutes les sessions de l'utilisateur, avec leurs jetons d'accès et de rafraîchissement, y compris la session utilisée.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} map[string]string "Déconnexion effectuée"
+// @Failure 401 {object} map[string]string "Non authentifié"
+// @Security BearerAuth
+// @Router /auth/logout-all [post]
+func LogoutAll(c *gin.Context) {
+	if _, err := revokeSessions(database.DB.Where("user_id = ?", c.GetUint("user_id"))); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la révocation des jetons"})
+		return
+	}
+	if err := revokeAccessToken(c); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la révocation du token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Déconnexion de tous les appareils effectuée"})
+}
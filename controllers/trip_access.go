@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"travelmate-api/database"
+	"travelmate-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// tripRole retourne le rôle de l'utilisateur sur le voyage, ou une chaîne vide s'il n'y a pas accès
+func tripRole(trip *models.Trip, userID uint) string {
+	if trip.UserID != 0 && trip.UserID == userID {
+		return models.RoleOwner
+	}
+	var member models.TripMember
+	if err := database.DB.Where("trip_id = ? AND user_id = ?", trip.ID, userID).First(&member).Error; err != nil {
+		return ""
+	}
+	return member.Role
+}
+
+// canAccessTrip indique si l'utilisateur connecté a au moins le rôle demandé sur le voyage.
+// Les administrateurs ont accès à tous les voyages.
+func canAccessTrip(c *gin.Context, trip *models.Trip, role string) bool {
+	if c.GetBool("is_admin") {
+		return true
+	}
+	return models.RoleRank(tripRole(trip, c.GetUint("user_id"))) >= models.RoleRank(role)
+}
+
+// loadTrip récupère le voyage désigné par le paramètre :id et vérifie que l'utilisateur
+// connecté a au moins le rôle demandé. Répond 404 ou 403 sinon.
+func loadTrip(c *gin.Context, role string) (*models.Trip, bool) {
+	var trip models.Trip
+	if err := database.DB.First(&trip, paramID(c, "id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Voyage non trouvé"})
+		return nil, false
+	}
+	if !canAccessTrip(c, &trip, role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Accès refusé : droits insuffisants sur ce voyage"})
+		return nil, false
+	}
+	return &trip, true
+}
+
+// tripsVisibleTo limite une requête aux voyages dont l'utilisateur est propriétaire ou membre
+func tripsVisibleTo(query *gorm.DB, userID uint) *gorm.DB {
+	memberTrips := database.DB.Model(&models.TripMember{}).Select("trip_id").Where("user_id = ?", userID)
+	return query.Where("trips.user_id = ? OR trips.id IN (?)", userID, memberTrips)
+}
+
+// scopedTrips retourne une requête sur les voyages visibles par l'appelant.
+// Un utilisateur ne voit que ses voyages ; un administrateur peut demander ceux d'un autre
+// utilisateur (userParam) ou tous les voyages avec all=true.
+func scopedTrips(c *gin.Context, userParam string) (*gorm.DB, bool) {
+	query := database.DB.Model(&models.Trip{})
+	currentUserID := c.GetUint("user_id")
+	isAdmin := c.GetBool("is_admin")
+
+	if c.Query("all") == "true" {
+		if !isAdmin {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Seul un administrateur peut consulter tous les voyages"})
+			return nil, false
+		}
+		return query, true
+	}
+
+	userID := currentUserID
+	if userParam != "" {
+		id, err := strconv.ParseUint(userParam, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID utilisateur invalide"})
+			return nil, false
+		}
+		userID = uint(id)
+	}
+
+	if userID != currentUserID && !isAdmin {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Vous ne pouvez consulter que vos propres voyages"})
+		return nil, false
+	}
+	return tripsVisibleTo(query, userID), true
+}
+
+// Statuts possibles d'un voyage dans le rapport d'une opération groupée
+const (
+	bulkForbidden = "forbidden"
+	bulkNotFound  = "not_found"
+	bulkConflict  = "conflict"
+)
+
+// partitionTrips sépare les voyages sur lesquels l'utilisateur connecté a le rôle demandé
+// des voyages introuvables ou inaccessibles, dont le statut est retourné par ID
+func partitionTrips(c *gin.Context, ids []uint, role string) ([]uint, map[uint]string, error) {
+	var trips []models.Trip
+	if err := database.DB.Where("id IN ?", ids).Find(&trips).Error; err != nil {
+		return nil, nil, err
+	}
+	found := map[uint]*models.Trip{}
+	for i := range trips {
+		found[trips[i].ID] = &trips[i]
+	}
+
+	allowed := []uint{}
+	rejected := map[uint]string{}
+	seen := map[uint]bool{}
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		trip, ok := found[id]
+		if !ok {
+			rejected[id] = bulkNotFound
+		} else if !canAccessTrip(c, trip, role) {
+			rejected[id] = bulkForbidden
+		} else {
+			allowed = append(allowed, id)
+		}
+	}
+	return allowed, rejected, nil
+}
+
+// writeBulkReport répond avec le résultat de l'opération pour chaque ID, dans l'ordre de la requête.
+// Le code est 200 si tout a réussi, 207 si une partie seulement a été traitée et 403 si rien ne l'a été.
+func writeBulkReport(c *gin.Context, ids []uint, rejected map[uint]string, done string) {
+	results := []models.BulkTripResult{}
+	seen := map[uint]bool{}
+	processed := 0
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		status, ok := rejected[id]
+		if !ok {
+			status = done
+			processed++
+		}
+		results = append(results, models.BulkTripResult{ID: id, Status: status})
+	}
+
+	code := http.StatusOK
+	if processed == 0 {
+		code = http.StatusForbidden
+	} else if processed < len(results) {
+		code = http.StatusMultiStatus
+	}
+	c.JSON(code, gin.H{"processed": processed, "results": results})
+}
@@ -1,26 +1,47 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
+	"strings"
+	"unicode"
 
 	"travelmate-api/database"
 	"travelmate-api/models"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // GetTrips godoc
-// @Summary Liste tous les voyages
-// @Description Retourne tous les voyages enregistrés
+// @Summary Liste les voyages
+// @Description Retourne les voyages dont l'utilisateur connecté est propriétaire ou membre. Un administrateur peut demander ceux d'un utilisateur (userId) ou tous les voyages (all=true).
 // @Tags trips
 // @Produce json
+// @Param userId query int false "ID de l'utilisateur (administrateurs uniquement)"
+// @Param all query bool false "Tous les voyages (administrateurs uniquement)"
+// @Param page query int false "Numéro de page (1 par défaut)"
+// @Param limit query int false "Nombre de voyages par page (20 par défaut, 100 au maximum)"
+// @Param sort query string false "Tri : startDate, title ou createdAt, préfixé de - pour un ordre décroissant"
+// @Param from query string false "Voyages se terminant à partir de cette date (AAAA-MM-JJ)"
+// @Param to query string false "Voyages commençant au plus tard à cette date (AAAA-MM-JJ)"
+// @Param location query string false "Localisation (correspondance partielle)"
+// @Param status query string false "Statut du voyage (upcoming, ongoing ou past)"
 // @Success 200 {array} models.Trip
+// @Header 200 {integer} X-Total-Count "Nombre total de voyages correspondants"
+// @Header 200 {string} Link "Liens vers les pages first, prev, next et last"
+// @Failure 400 {object} map[string]string "Paramètre invalide"
+// @Failure 403 {object} map[string]string "Accès refusé"
 // @Router /trips [get]
 // @Security BearerAuth
 func GetTrips(c *gin.Context) {
-	var trips []models.Trip
-	if err := database.DB.Find(&trips).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération"})
+	query, ok := scopedTrips(c, c.Query("userId"))
+	if !ok {
+		return
+	}
+
+	trips, ok := paginateTrips(c, query)
+	if !ok {
 		return
 	}
 	c.JSON(http.StatusOK, trips)
@@ -28,38 +49,64 @@ func GetTrips(c *gin.Context) {
 
 // GetTripByID godoc
 // @Summary Récupérer un voyage par son ID
-// @Description Retourne les détails d’un voyage spécifique à partir de son ID
+// @Description Retourne les détails d’un voyage spécifique à partir de son ID. Avec include=itinerary, le programme jour par jour est inclus.
 // @Tags Trips
 // @Produce json
 // @Param id path int true "ID du voyage"
-// @Success 200 {object} models.Trip
+// @Param include query string false "Données à inclure (itinerary)"
+// @Success 200 {object} models.TripDetail
+// @Failure 403 {object} map[string]string "Accès refusé"
 // @Failure 404 {object} map[string]string "Voyage non trouvé"
+// @Security BearerAuth
 // @Router /trips/{id} [get]
 func GetTripByID(c *gin.Context) {
-	id := c.Param("id")
-	var trip models.Trip
-	if err := database.DB.First(&trip, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Voyage non trouvé"})
+	trip, ok := loadTrip(c, models.RoleViewer)
+	if !ok {
 		return
 	}
-	c.JSON(http.StatusOK, trip)
+
+	if c.Query("include") != "itinerary" {
+		c.JSON(http.StatusOK, trip)
+		return
+	}
+
+	days, err := loadItinerary(trip.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération du programme"})
+		return
+	}
+	c.JSON(http.StatusOK, models.TripDetail{Trip: *trip, Days: days})
 }
 
 // GetTripsByUserID godoc
 // @Summary Récupérer les voyages d’un utilisateur
-// @Description Retourne tous les voyages associés à un utilisateur donné
+// @Description Retourne les voyages dont un utilisateur est propriétaire ou membre. Un utilisateur ne peut consulter que les siens, sauf s'il est administrateur.
 // @Tags Trips
 // @Produce json
 // @Param id path int true "ID de l'utilisateur"
+// @Param page query int false "Numéro de page (1 par défaut)"
+// @Param limit query int false "Nombre de voyages par page (20 par défaut, 100 au maximum)"
+// @Param sort query string false "Tri : startDate, title ou createdAt, préfixé de - pour un ordre décroissant"
+// @Param from query string false "Voyages se terminant à partir de cette date (AAAA-MM-JJ)"
+// @Param to query string false "Voyages commençant au plus tard à cette date (AAAA-MM-JJ)"
+// @Param location query string false "Localisation (correspondance partielle)"
+// @Param status query string false "Statut du voyage (upcoming, ongoing ou past)"
 // @Success 200 {array} models.Trip
+// @Header 200 {integer} X-Total-Count "Nombre total de voyages correspondants"
+// @Header 200 {string} Link "Liens vers les pages first, prev, next et last"
+// @Failure 400 {object} map[string]string "Paramètre invalide"
+// @Failure 403 {object} map[string]string "Accès refusé"
 // @Failure 500 {object} map[string]string "Erreur lors de la récupération des voyages"
-// @Router /trips/user/{id} [get]
+// @Security BearerAuth
+// @Router /users/{id}/trips [get]
 func GetTripsByUserID(c *gin.Context) {
-	id := c.Param("id")
+	query, ok := scopedTrips(c, c.Param("id"))
+	if !ok {
+		return
+	}
 
-	var trips []models.Trip
-	if err := database.DB.Where("user_id = ?", id).Find(&trips).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des voyages"})
+	trips, ok := paginateTrips(c, query)
+	if !ok {
 		return
 	}
 
@@ -68,14 +115,15 @@ func GetTripsByUserID(c *gin.Context) {
 
 // CreateTrip godoc
 // @Summary Créer un voyage
-// @Description Crée un nouveau voyage avec les informations fournies
+// @Description Crée un nouveau voyage avec les informations fournies. Le voyage appartient toujours à l'utilisateur connecté.
 // @Tags Trips
 // @Accept json
 // @Produce json
 // @Param trip body models.Trip true "Données du voyage"
 // @Success 201 {object} models.Trip
-// @Failure 400 {object} map[string]string "Format invalide"
+// @Failure 400 {object} map[string]string "Format invalide ou coordonnées invalides"
 // @Failure 500 {object} map[string]string "Erreur de création"
+// @Security BearerAuth
 // @Router /trips [post]
 func CreateTrip(c *gin.Context) {
 	var trip models.Trip
@@ -83,50 +131,169 @@ func CreateTrip(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Format invalide"})
 		return
 	}
-	if err := database.DB.Create(&trip).Error; err != nil {
+	trip.ID = 0
+	trip.UserID = c.GetUint("user_id")
+	if err := validateTripCoordinates(&trip); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return createTrip(tx, &trip)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur de création"})
 		return
 	}
 	c.JSON(http.StatusCreated, trip)
 }
 
+// createTrip enregistre un voyage et son propriétaire comme membre du voyage
+func createTrip(tx *gorm.DB, trip *models.Trip) error {
+	if err := tx.Create(trip).Error; err != nil {
+		return err
+	}
+	owner := models.TripMember{TripID: trip.ID, UserID: trip.UserID, Role: models.RoleOwner}
+	return tx.Omit("User").Create(&owner).Error
+}
+
 // UpdateTrip godoc
 // @Summary Mettre à jour un voyage
-// @Description Met à jour un voyage existant avec les nouvelles données fournies
+// @Description Met à jour un voyage existant avec les nouvelles données fournies. Nécessite le rôle editor ou owner sur le voyage. Les nouvelles dates doivent inclure toutes les journées du programme.
 // @Tags Trips
 // @Accept json
 // @Produce json
 // @Param id path int true "ID du voyage"
 // @Param trip body models.Trip true "Nouvelles données du voyage"
 // @Success 200 {object} models.Trip
-// @Failure 400 {object} map[string]string "Format invalide"
+// @Failure 400 {object} map[string]string "Format invalide ou coordonnées invalides"
+// @Failure 403 {object} map[string]string "Accès refusé"
 // @Failure 404 {object} map[string]string "Voyage non trouvé"
+// @Failure 409 {object} object{error=string,days=[]string} "Journées du programme en dehors des nouvelles dates"
+// @Security BearerAuth
 // @Router /trips/{id} [put]
 func UpdateTrip(c *gin.Context) {
-	id := c.Param("id")
-	var trip models.Trip
-	if err := database.DB.First(&trip, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Voyage non trouvé"})
+	trip, ok := loadTrip(c, models.RoleEditor)
+	if !ok {
 		return
 	}
-	if err := c.ShouldBindJSON(&trip); err != nil {
+
+	// L'identifiant et le propriétaire ne sont pas modifiables
+	id, ownerID := trip.ID, trip.UserID
+	startDate, endDate := trip.StartDate, trip.EndDate
+	if err := c.ShouldBindJSON(trip); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Format invalide"})
 		return
 	}
-	database.DB.Save(&trip)
+	trip.ID, trip.UserID = id, ownerID
+	if err := validateTripCoordinates(trip); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if trip.StartDate != startDate || trip.EndDate != endDate {
+		conflicts, err := conflictingDays(trip)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la vérification du programme"})
+			return
+		}
+		if len(conflicts) > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "Les dates du voyage (au format AAAA-MM-JJ) doivent inclure toutes les journées du programme", "days": conflicts})
+			return
+		}
+	}
+
+	database.DB.Save(trip)
 	c.JSON(http.StatusOK, trip)
 }
 
+// tripUpdateColumns associe les champs modifiables par une mise à jour groupée à leur colonne.
+// Les noms sont comparés sans tenir compte de la casse ni des « _ » : startDate, start_date
+// et StartDate désignent la même colonne.
+var tripUpdateColumns = map[string]string{
+	"title":       "title",
+	"description": "description",
+	"location":    "location",
+	"startdate":   "start_date",
+	"enddate":     "end_date",
+	"latitude":    "latitude",
+	"longitude":   "longitude",
+	"notes":       "notes",
+}
+
+// normalizeTripUpdate retourne la mise à jour indexée par colonne, et refuse les champs non
+// modifiables (identifiant, propriétaire...) et les valeurs du mauvais type
+func normalizeTripUpdate(update map[string]interface{}) (map[string]interface{}, error) {
+	normalized := map[string]interface{}{}
+	for field, value := range update {
+		column, ok := tripUpdateColumns[strings.ToLower(strings.ReplaceAll(field, "_", ""))]
+		if !ok {
+			return nil, errors.New("Champ non modifiable : " + field)
+		}
+		if _, duplicate := normalized[column]; duplicate {
+			return nil, errors.New("Champ en double : " + field)
+		}
+		switch column {
+		case "latitude", "longitude":
+			if _, ok := value.(float64); !ok {
+				return nil, errors.New("Le champ " + field + " doit être un nombre")
+			}
+		default:
+			text, ok := value.(string)
+			if !ok {
+				return nil, errors.New("Le champ " + field + " doit être une chaîne de caractères")
+			}
+			if column == "title" && strings.TrimSpace(text) == "" {
+				return nil, errors.New("Le titre ne peut pas être vide")
+			}
+		}
+		normalized[column] = value
+	}
+	return normalized, nil
+}
+
+// excludeDateConflicts retire des voyages à modifier ceux dont les nouvelles dates excluraient
+// des journées du programme, et les signale en conflit
+func excludeDateConflicts(ids []uint, update map[string]interface{}, rejected map[uint]string) ([]uint, error) {
+	var trips []models.Trip
+	if err := database.DB.Where("id IN ?", ids).Order("id").Find(&trips).Error; err != nil {
+		return nil, err
+	}
+
+	kept := []uint{}
+	for i := range trips {
+		trip := &trips[i]
+		if startDate, ok := update["start_date"].(string); ok {
+			trip.StartDate = startDate
+		}
+		if endDate, ok := update["end_date"].(string); ok {
+			trip.EndDate = endDate
+		}
+		conflicts, err := conflictingDays(trip)
+		if err != nil {
+			return nil, err
+		}
+		if len(conflicts) > 0 {
+			rejected[trip.ID] = bulkConflict
+			continue
+		}
+		kept = append(kept, trip.ID)
+	}
+	return kept, nil
+}
+
 // UpdateMultipleTrips godoc
 // @Summary Mettre à jour plusieurs voyages
-// @Description Met à jour les champs spécifiés pour une liste de voyages
+// @Description Met à jour les champs spécifiés pour une liste de voyages : title, description, location, startDate, endDate, latitude, longitude et notes ; tout autre champ est refusé. Seuls les voyages sur lesquels l'utilisateur a le rôle editor ou owner sont modifiés, et les dates d'un voyage ne sont pas modifiées si elles excluent des journées de son programme ; le résultat est détaillé pour chaque ID (updated, forbidden, not_found ou conflict).
 // @Tags Trips
 // @Accept json
 // @Produce json
 // @Param update body object{ids=[]uint,update=map[string]interface{}} true "Liste des IDs et des champs à mettre à jour"
-// @Success 200 {object} map[string]string "Mise à jour effectuée"
-// @Failure 400 {object} map[string]string "Format invalide ou données manquantes"
+// @Success 200 {object} object{processed=int,results=[]models.BulkTripResult} "Tous les voyages ont été mis à jour"
+// @Success 207 {object} object{processed=int,results=[]models.BulkTripResult} "Une partie des voyages a été mise à jour"
+// @Failure 400 {object} map[string]string "Format invalide, données manquantes ou coordonnées invalides"
+// @Failure 403 {object} object{processed=int,results=[]models.BulkTripResult} "Aucun voyage n'a pu être mis à jour"
 // @Failure 500 {object} map[string]string "Erreur lors de la mise à jour"
+// @Security BearerAuth
 // @Router /trips [put]
 func UpdateMultipleTrips(c *gin.Context) {
 	var payload struct {
@@ -143,18 +310,44 @@ func UpdateMultipleTrips(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "IDs ou données manquantes"})
 		return
 	}
+	update, err := normalizeTripUpdate(payload.Update)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateCoordinateUpdate(update); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	if err := database.DB.Model(&models.Trip{}).Where("id IN ?", payload.IDs).Updates(payload.Update).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la mise à jour"})
+	allowed, rejected, err := partitionTrips(c, payload.IDs, models.RoleEditor)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la vérification des droits"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Mise à jour effectuée"})
+	_, startChanged := update["start_date"]
+	_, endChanged := update["end_date"]
+	if startChanged || endChanged {
+		if allowed, err = excludeDateConflicts(allowed, update, rejected); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la vérification du programme"})
+			return
+		}
+	}
+
+	if len(allowed) > 0 {
+		if err := database.DB.Model(&models.Trip{}).Where("id IN ?", allowed).Updates(update).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la mise à jour"})
+			return
+		}
+	}
+
+	writeBulkReport(c, payload.IDs, rejected, "updated")
 }
 
 // DeleteTrip godoc
 // @Summary Supprimer un voyage
-// @Description Supprime un voyage par son ID si l'utilisateur est le propriétaire ou un administrateur
+// @Description Supprime un voyage par son ID si l'utilisateur en est propriétaire (rôle owner) ou administrateur
 // @Tags Trips
 // @Produce json
 // @Param id path int true "ID du voyage à supprimer"
@@ -177,22 +370,19 @@ func DeleteTrip(c *gin.Context) {
 	}
 
 	// Récupère l'utilisateur connecté
-	currentUserID, exists := c.Get("user_id")
-	if !exists {
+	if _, exists := c.Get("user_id"); !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Utilisateur non authentifié"})
 		return
 	}
 
-	isAdmin, _ := c.Get("is_admin")
-
 	// Vérifie si l'utilisateur est propriétaire ou admin
-	if trip.UserID != currentUserID.(uint) && !isAdmin.(bool) {
+	if !canAccessTrip(c, &trip, models.RoleOwner) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Accès refusé : vous ne pouvez pas supprimer ce voyage"})
 		return
 	}
 
 	// Supprime le voyage
-	if err := database.DB.Delete(&trip).Error; err != nil {
+	if err := deleteTrips([]uint{trip.ID}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la suppression"})
 		return
 	}
@@ -200,6 +390,20 @@ func DeleteTrip(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Le voyage a bien été supprimé"})
 }
 
+// DeleteMultipleTrips godoc
+// @Summary Supprimer plusieurs voyages
+// @Description Supprime une liste de voyages. Seuls les voyages dont l'utilisateur est propriétaire (rôle owner) sont supprimés ; le résultat est détaillé pour chaque ID (deleted, forbidden ou not_found).
+// @Tags Trips
+// @Accept json
+// @Produce json
+// @Param ids body object{ids=[]uint} true "Liste des IDs à supprimer"
+// @Success 200 {object} object{processed=int,results=[]models.BulkTripResult} "Tous les voyages ont été supprimés"
+// @Success 207 {object} object{processed=int,results=[]models.BulkTripResult} "Une partie des voyages a été supprimée"
+// @Failure 400 {object} map[string]string "Format invalide"
+// @Failure 403 {object} object{processed=int,results=[]models.BulkTripResult} "Aucun voyage n'a pu être supprimé"
+// @Failure 500 {object} map[string]string "Erreur lors de la suppression"
+// @Security BearerAuth
+// @Router /trips [delete]
 func DeleteMultipleTrips(c *gin.Context) {
 	var payload struct {
 		IDs []uint `json:"ids"`
@@ -215,44 +419,123 @@ func DeleteMultipleTrips(c *gin.Context) {
 		return
 	}
 
-	if err := database.DB.Where("id IN ?", payload.IDs).Delete(&models.Trip{}).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la suppression"})
+	allowed, rejected, err := partitionTrips(c, payload.IDs, models.RoleOwner)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la vérification des droits"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Suppression effectuée"})
+	if len(allowed) > 0 {
+		if err := deleteTrips(allowed); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la suppression"})
+			return
+		}
+	}
+
+	writeBulkReport(c, payload.IDs, rejected, "deleted")
+}
+
+// deleteTrips supprime les voyages ainsi que les données qui leur sont rattachées
+func deleteTrips(ids []uint) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		dayIDs := tx.Model(&models.ItineraryDay{}).Select("id").Where("trip_id IN ?", ids)
+		if err := tx.Where("day_id IN (?)", dayIDs).Delete(&models.Stop{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("trip_id IN ?", ids).Delete(&models.ItineraryDay{}).Error; err != nil {
+			return err
+		}
+		expenseIDs := tx.Model(&models.Expense{}).Select("id").Where("trip_id IN ?", ids)
+		if err := tx.Where("expense_id IN (?)", expenseIDs).Delete(&models.ExpenseSplit{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("trip_id IN ?", ids).Delete(&models.Expense{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("trip_id IN ?", ids).Delete(&models.Settlement{}).Error; err != nil {
+			return err
+		}
+		budgetIDs := tx.Model(&models.Budget{}).Select("id").Where("trip_id IN ?", ids)
+		if err := tx.Where("budget_id IN (?)", budgetIDs).Delete(&models.BudgetCategory{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("trip_id IN ?", ids).Delete(&models.Budget{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("trip_id IN ?", ids).Delete(&models.TripMember{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("trip_id IN ?", ids).Delete(&models.Invitation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("trip_id IN ?", ids).Delete(&models.ShareLink{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", ids).Delete(&models.Trip{}).Error
+	})
+}
+
+// searchMatchQuery transforme la saisie de l'utilisateur en requête FTS5 : chaque mot
+// devient un préfixe entre guillemets, ce qui neutralise la syntaxe de FTS5
+func searchMatchQuery(input string) string {
+	words := strings.FieldsFunc(input, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		terms = append(terms, `"`+word+`"*`)
+	}
+	return strings.Join(terms, " ")
 }
 
 // SearchTrips godoc
 // @Summary Rechercher des voyages
-// @Description Recherche les voyages dont un champ contient la sous-chaîne donnée (titre, description, localisation, dates)
+// @Description Recherche plein texte, parmi les voyages visibles par l'utilisateur, dans le titre, la description, la localisation et les notes. Chaque mot est recherché comme préfixe, sans tenir compte des accents ni de la casse. Les résultats sont triés par pertinence (sauf paramètre sort) et accompagnés d'un extrait où les mots trouvés sont entourés de <mark>.
 // @Tags Trips
 // @Accept json
 // @Produce json
-// @Param query query string true "Terme de recherche (doit correspondre partiellement à un champ du voyage)"
-// @Success 200 {array} models.Trip
+// @Param query query string true "Mots recherchés"
+// @Param userId query int false "ID de l'utilisateur (administrateurs uniquement)"
+// @Param all query bool false "Tous les voyages (administrateurs uniquement)"
+// @Param page query int false "Numéro de page (1 par défaut)"
+// @Param limit query int false "Nombre de voyages par page (20 par défaut, 100 au maximum)"
+// @Param sort query string false "Tri : startDate, title ou createdAt, préfixé de - pour un ordre décroissant (pertinence par défaut)"
+// @Param from query string false "Voyages se terminant à partir de cette date (AAAA-MM-JJ)"
+// @Param to query string false "Voyages commençant au plus tard à cette date (AAAA-MM-JJ)"
+// @Param location query string false "Localisation (correspondance partielle)"
+// @Param status query string false "Statut du voyage (upcoming, ongoing ou past)"
+// @Success 200 {array} models.TripSearchResult
+// @Header 200 {integer} X-Total-Count "Nombre total de voyages correspondants"
+// @Header 200 {string} Link "Liens vers les pages first, prev, next et last"
 // @Failure 400 {object} map[string]string "Le paramètre 'query' est requis"
+// @Failure 403 {object} map[string]string "Accès refusé"
 // @Failure 500 {object} map[string]string "Erreur lors de la recherche"
+// @Security BearerAuth
 // @Router /trips/search [get]
 func SearchTrips(c *gin.Context) {
-	query := c.Query("query")
-	if query == "" {
+	match := searchMatchQuery(c.Query("query"))
+	if match == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Le paramètre 'query' est requis"})
 		return
 	}
 
-	var trips []models.Trip
-	searchPattern := "%" + query + "%"
+	scope, ok := scopedTrips(c, c.Query("userId"))
+	if !ok {
+		return
+	}
 
-	err := database.DB.Where(
-		"title LIKE ? OR description LIKE ? OR location LIKE ? OR start_date LIKE ? OR end_date ILIKE ?",
-		searchPattern, searchPattern, searchPattern, searchPattern, searchPattern,
-	).Find(&trips).Error
+	// bm25 est d'autant plus petit que le voyage est pertinent ; le titre et la
+	// localisation pèsent plus que la description et les notes
+	const rank = "bm25(trips_fts, 10.0, 2.0, 5.0, 1.0)"
+	scope = scope.
+		Select("trips.*, snippet(trips_fts, -1, '<mark>', '</mark>', '…', 12) AS snippet, -" + rank + " AS score").
+		Joins("JOIN trips_fts ON trips_fts.rowid = trips.id").
+		Where("trips_fts MATCH ?", match)
 
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la recherche"})
+	results := []models.TripSearchResult{}
+	if !paginateInto(c, scope, &results, rank+", trips.id") {
 		return
 	}
 
-	c.JSON(http.StatusOK, trips)
+	c.JSON(http.StatusOK, results)
 }
@@ -0,0 +1,224 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"travelmate-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// Statuts d'un voyage, déduits de ses dates par rapport à aujourd'hui
+const (
+	tripUpcoming = "upcoming"
+	tripOngoing  = "ongoing"
+	tripPast     = "past"
+)
+
+// tripStatus retourne le statut du voyage à la date du jour (AAAA-MM-JJ)
+func tripStatus(trip *models.Trip, today string) string {
+	switch {
+	case trip.StartDate > today:
+		return tripUpcoming
+	case trip.EndDate < today:
+		return tripPast
+	default:
+		return tripOngoing
+	}
+}
+
+// tripSortColumns associe les valeurs du paramètre sort aux colonnes triables.
+// L'ordre de création correspond à l'ordre des IDs.
+var tripSortColumns = map[string]string{
+	"startDate": "trips.start_date",
+	"title":     "trips.title",
+	"createdAt": "trips.id",
+}
+
+// listOptions regroupe les paramètres de pagination, de tri et de filtre d'une liste de voyages
+type listOptions struct {
+	Page     int
+	Limit    int
+	Sort     string
+	Desc     bool
+	From     string
+	To       string
+	Location string
+	Status   string
+}
+
+// parseListOptions lit et valide les paramètres de la requête
+func parseListOptions(c *gin.Context) (*listOptions, error) {
+	opts := &listOptions{Page: 1, Limit: defaultPageSize}
+
+	if value := c.Query("page"); value != "" {
+		page, err := strconv.Atoi(value)
+		if err != nil || page < 1 {
+			return nil, errors.New("Le paramètre 'page' doit être un entier positif")
+		}
+		opts.Page = page
+	}
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxPageSize {
+			return nil, fmt.Errorf("Le paramètre 'limit' doit être compris entre 1 et %d", maxPageSize)
+		}
+		opts.Limit = limit
+	}
+
+	if value := c.Query("sort"); value != "" {
+		opts.Desc = strings.HasPrefix(value, "-")
+		opts.Sort = strings.TrimPrefix(value, "-")
+		if _, ok := tripSortColumns[opts.Sort]; !ok {
+			return nil, errors.New("Le paramètre 'sort' doit valoir startDate, title ou createdAt (préfixé de - pour un tri décroissant)")
+		}
+	}
+
+	opts.From = c.Query("from")
+	opts.To = c.Query("to")
+	for _, date := range []string{opts.From, opts.To} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse(dateLayout, date); err != nil {
+			return nil, errors.New("Les paramètres 'from' et 'to' doivent être au format AAAA-MM-JJ")
+		}
+	}
+	if opts.From != "" && opts.To != "" && opts.From > opts.To {
+		return nil, errors.New("Le paramètre 'from' doit précéder 'to'")
+	}
+
+	opts.Location = strings.TrimSpace(c.Query("location"))
+
+	opts.Status = c.Query("status")
+	switch opts.Status {
+	case "", tripUpcoming, tripOngoing, tripPast:
+	default:
+		return nil, errors.New("Le paramètre 'status' doit valoir upcoming, ongoing ou past")
+	}
+	return opts, nil
+}
+
+// filter applique les filtres de dates, de localisation et de statut à la requête.
+// Les dates sont stockées au format AAAA-MM-JJ, l'ordre alphabétique est donc chronologique.
+func (opts *listOptions) filter(query *gorm.DB) *gorm.DB {
+	// Un voyage correspond à la période s'il la chevauche
+	if opts.From != "" {
+		query = query.Where("trips.end_date >= ?", opts.From)
+	}
+	if opts.To != "" {
+		query = query.Where("trips.start_date <= ?", opts.To)
+	}
+	if opts.Location != "" {
+		query = query.Where("trips.location LIKE ?", "%"+opts.Location+"%")
+	}
+
+	today := time.Now().Format(dateLayout)
+	switch opts.Status {
+	case tripUpcoming:
+		query = query.Where("trips.start_date > ?", today)
+	case tripOngoing:
+		query = query.Where("trips.start_date <= ? AND trips.end_date >= ?", today, today)
+	case tripPast:
+		query = query.Where("trips.end_date < ?", today)
+	}
+	return query
+}
+
+// order retourne la clause de tri, départagée par l'ID pour une pagination stable.
+// Sans paramètre sort, l'ordre par défaut est utilisé, ou à défaut l'ordre de création.
+func (opts *listOptions) order(defaultOrder string) string {
+	if opts.Sort == "" {
+		if defaultOrder != "" {
+			return defaultOrder
+		}
+		return "trips.id ASC"
+	}
+	direction := "ASC"
+	if opts.Desc {
+		direction = "DESC"
+	}
+	column := tripSortColumns[opts.Sort]
+	if column == "trips.id" {
+		return column + " " + direction
+	}
+	return column + " " + direction + ", trips.id " + direction
+}
+
+// paginateTrips filtre, trie et découpe en pages une requête sur les voyages.
+// Le nombre total de résultats est retourné dans l'en-tête X-Total-Count et les liens
+// vers les autres pages dans l'en-tête Link. Répond 400 ou 500 en cas d'erreur.
+func paginateTrips(c *gin.Context, query *gorm.DB) ([]models.Trip, bool) {
+	trips := []models.Trip{}
+	ok := paginateInto(c, query, &trips, "")
+	return trips, ok
+}
+
+// paginateInto fait comme paginateTrips mais charge la page dans dest,
+// triée par defaultOrder si le paramètre sort est absent
+func paginateInto(c *gin.Context, query *gorm.DB, dest interface{}, defaultOrder string) bool {
+	opts, err := parseListOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	query = opts.filter(query)
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération"})
+		return false
+	}
+
+	err = query.Order(opts.order(defaultOrder)).Limit(opts.Limit).Offset((opts.Page - 1) * opts.Limit).Find(dest).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération"})
+		return false
+	}
+
+	setPageHeaders(c, opts, total)
+	return true
+}
+
+// setPageHeaders ajoute les en-têtes X-Total-Count et Link à la réponse
+func setPageHeaders(c *gin.Context, opts *listOptions, total int64) {
+	c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+	c.Header("Link", paginationLinks(c, opts, total))
+}
+
+// paginationLinks construit l'en-tête Link (RFC 8288) vers les pages first, prev, next et last
+func paginationLinks(c *gin.Context, opts *listOptions, total int64) string {
+	lastPage := int(math.Ceil(float64(total) / float64(opts.Limit)))
+	if lastPage < 1 {
+		lastPage = 1
+	}
+
+	pageURL := func(page int) string {
+		values := c.Request.URL.Query()
+		values.Set("page", strconv.Itoa(page))
+		values.Set("limit", strconv.Itoa(opts.Limit))
+		return requestBaseURL(c) + c.Request.URL.Path + "?" + values.Encode()
+	}
+
+	links := []string{fmt.Sprintf(`<%s>; rel="first"`, pageURL(1))}
+	if opts.Page > 1 {
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, pageURL(min(opts.Page-1, lastPage))))
+	}
+	if opts.Page < lastPage {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, pageURL(opts.Page+1)))
+	}
+	links = append(links, fmt.Sprintf(`<%s>; rel="last"`, pageURL(lastPage)))
+	return strings.Join(links, ", ")
+}
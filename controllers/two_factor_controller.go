@@ -0,0 +1,397 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"time"
+
+	"travelmate-api/database"
+	"travelmate-api/logger"
+	"travelmate-api/middleware"
+	"travelmate-api/models"
+	"travelmate-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	// Émetteur affiché dans l'application d'authentification
+	twoFactorIssuer = "TravelMate"
+	// Durée laissée pour saisir le code après la vérification du mot de passe
+	twoFactorChallengeTTL = 5 * time.Minute
+	// Codes invalides tolérés pour un même jeton de connexion, au-delà desquels il faut se reconnecter
+	twoFactorChallengeMaxFailures = 5
+	recoveryCodeCount             = 10
+	qrCodeSize                    = 256
+)
+
+// errSecondFactorInvalid signale un code TOTP ou de secours invalide ou déjà utilisé
+var errSecondFactorInvalid = errors.New("second factor invalid")
+
+// generateRecoveryCodes remplace les codes de secours de l'utilisateur et retourne les nouveaux codes en clair
+func generateRecoveryCodes(tx *gorm.DB, userID uint) ([]string, error) {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+	codes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := utils.GenerateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.Create(&models.RecoveryCode{UserID: userID, CodeHash: utils.HashToken(code)}).Error; err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// verifyTOTP vérifie un code TOTP et l'enregistre comme utilisé : un même code ne peut pas
+// servir deux fois, même en cas de requêtes simultanées
+func verifyTOTP(tx *gorm.DB, user *models.User, code string) error {
+	step, ok := utils.ValidateTOTP(user.TOTPSecret, code, time.Now(), user.TOTPLastStep)
+	if !ok {
+		return errSecondFactorInvalid
+	}
+	result := tx.Model(&models.User{}).Where("id = ? AND totp_last_step < ?", user.ID, step).Update("totp_last_step", step)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errSecondFactorInvalid
+	}
+	user.TOTPLastStep = step
+	return nil
+}
+
+// verifySecondFactor vérifie un code TOTP ou, à défaut, un code de secours, qui est alors consommé
+func verifySecondFactor(tx *gorm.DB, user *models.User, code, recoveryCode string) error {
+	if code != "" {
+		return verifyTOTP(tx, user, code)
+	}
+	if recoveryCode == "" {
+		return errSecondFactorInvalid
+	}
+
+	hash := utils.HashToken(utils.NormalizeRecoveryCode(recoveryCode))
+	result := tx.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", user.ID, hash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errSecondFactorInvalid
+	}
+	return nil
+}
+
+// respondSecondFactorError traduit une erreur de vérification du second facteur en réponse HTTP
+func respondSecondFactorError(c *gin.Context, err error) {
+	if errors.Is(err, errSecondFactorInvalid) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Code de vérification invalide"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la vérification du code"})
+}
+
+// twoFactorChallenge retourne le jeton permettant de terminer la connexion avec un code
+func twoFactorChallenge(c *gin.Context, user *models.User) {
+	database.DB.Where("key LIKE ? AND last_failure_at < ?", challengeThrottle().Prefix+"%", time.Now().Add(-twoFactorChallengeTTL)).Delete(&models.LoginThrottle{})
+
+	token, err := utils.GenerateChallengeToken(user.ID, time.Now().Add(twoFactorChallengeTTL))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la génération du token"})
+		return
+	}
+	c.JSON(http.StatusAccepted, models.TwoFactorChallenge{
+		TwoFactorRequired: true,
+		ChallengeToken:    token,
+		ExpiresIn:         int(twoFactorChallengeTTL.Seconds()),
+	})
+}
+
+// challengeThrottle limite les codes invalides saisis avec un même jeton de connexion : il est
+// inutilisable jusqu'à son expiration après twoFactorChallengeMaxFailures échecs
+func challengeThrottle() loginThrottlePolicy {
+	return loginThrottlePolicy{
+		Prefix:       "challenge:",
+		FreeAttempts: twoFactorChallengeMaxFailures,
+		MaxFailures:  twoFactorChallengeMaxFailures,
+		Lockout:      twoFactorChallengeTTL,
+	}
+}
+
+// SetupTwoFactor godoc
+// @Summary Activer la double authentification
+// @Description Génère un secret TOTP (RFC 6238) à enregistrer dans une application d'authentification, sous forme d'URI otpauth:// et de QR code PNG. La double authentification n'est active qu'après confirmation avec POST /me/2fa/confirm ; relancer l'activation remplace le secret non confirmé.
+// @Tags 2FA
+// @Produce json
+// @Success 200 {object} models.TwoFactorSetup
+// @Failure 409 {object} map[string]string "Double authentification déjà active"
+// @Security BearerAuth
+// @Router /me/2fa/setup [post]
+func SetupTwoFactor(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	if user.TwoFactorEnabled {
+		c.JSON(http.StatusConflict, gin.H{"error": "La double authentification est déjà active"})
+		return
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la génération du secret"})
+		return
+	}
+	uri := utils.TOTPURI(twoFactorIssuer, user.Email, secret)
+	png, err := qrcode.Encode(uri, qrcode.Medium, qrCodeSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la génération du QR code"})
+		return
+	}
+
+	if err := database.DB.Model(user).Updates(map[string]interface{}{"totp_secret": secret, "totp_last_step": 0}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de l'enregistrement du secret"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.TwoFactorSetup{
+		Secret:     secret,
+		OTPAuthURI: uri,
+		QRCode:     "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+	})
+}
+
+// ConfirmTwoFactor godoc
+// @Summary Confirmer la double authentification
+// @Description Active la double authentification à l'aide d'un premier code généré par l'application, et retourne les codes de secours à usage unique. Ces codes ne sont affichés qu'une fois.
+// @Tags 2FA
+// @Accept json
+// @Produce json
+// @Param input body object{code=string} true "Code à 6 chiffres"
+// @Success 200 {object} models.RecoveryCodes
+// @Failure 400 {object} map[string]string "Code manquant ou activation non commencée"
+// @Failure 401 {object} map[string]string "Code invalide"
+// @Failure 409 {object} map[string]string "Double authentification déjà active"
+// @Security BearerAuth
+// @Router /me/2fa/confirm [post]
+func ConfirmTwoFactor(c *gin.Context) {
+	var input struct {
+		Code string `json:"code" form:"code" binding:"required"`
+	}
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le code est requis"})
+		return
+	}
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	if user.TwoFactorEnabled {
+		c.JSON(http.StatusConflict, gin.H{"error": "La double authentification est déjà active"})
+		return
+	}
+	if user.TOTPSecret == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Commencez par POST /me/2fa/setup"})
+		return
+	}
+
+	var codes []string
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := verifyTOTP(tx, user, input.Code); err != nil {
+			return err
+		}
+		if err := tx.Model(user).Update("two_factor_enabled", true).Error; err != nil {
+			return err
+		}
+		var err error
+		codes, err = generateRecoveryCodes(tx, user.ID)
+		return err
+	})
+	if err != nil {
+		respondSecondFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.RecoveryCodes{RecoveryCodes: codes})
+}
+
+// DisableTwoFactor godoc
+// @Summary Désactiver la double authentification
+// @Description Désactive la double authentification après vérification du mot de passe et d'un code (TOTP ou de secours). Impossible pour un administrateur si elle est obligatoire.
+// @Tags 2FA
+// @Accept json
+// @Produce json
+// @Param input body object{password=string,code=string,recoveryCode=string} true "Mot de passe, et code TOTP ou code de secours"
+// @Success 200 {object} map[string]string "Double authentification désactivée"
+// @Failure 400 {object} map[string]string "Champs manquants"
+// @Failure 401 {object} map[string]string "Mot de passe ou code invalide"
+// @Failure 403 {object} map[string]string "Double authentification obligatoire"
+// @Failure 409 {object} map[string]string "Double authentification inactive"
+// @Security BearerAuth
+// @Router /me/2fa [delete]
+func DisableTwoFactor(c *gin.Context) {
+	var input struct {
+		Password     string `json:"password" form:"password" binding:"required"`
+		Code         string `json:"code" form:"code"`
+		RecoveryCode string `json:"recoveryCode" form:"recoveryCode"`
+	}
+	if err := c.ShouldBind(&input); err != nil || (input.Code == "" && input.RecoveryCode == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le mot de passe et un code (code ou recoveryCode) sont requis"})
+		return
+	}
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	if !user.TwoFactorEnabled {
+		c.JSON(http.StatusConflict, gin.H{"error": "La double authentification n'est pas active"})
+		return
+	}
+	if user.IsAdmin && middleware.AdminTwoFactorRequired() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "La double authentification est obligatoire pour les administrateurs"})
+		return
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.Password)); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Mot de passe incorrect"})
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := verifySecondFactor(tx, user, input.Code, input.RecoveryCode); err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", user.ID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(user).Updates(map[string]interface{}{"two_factor_enabled": false, "totp_secret": "", "totp_last_step": 0}).Error
+	})
+	if err != nil {
+		respondSecondFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Double authentification désactivée"})
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Renouveler les codes de secours
+// @Description Remplace les codes de secours après vérification d'un code TOTP. Les anciens codes ne sont plus valables.
+// @Tags 2FA
+// @Accept json
+// @Produce json
+// @Param input body object{code=string} true "Code à 6 chiffres"
+// @Success 200 {object} models.RecoveryCodes
+// @Failure 401 {object} map[string]string "Code invalide"
+// @Failure 409 {object} map[string]string "Double authentification inactive"
+// @Security BearerAuth
+// @Router /me/2fa/recovery-codes [post]
+func RegenerateRecoveryCodes(c *gin.Context) {
+	var input struct {
+		Code string `json:"code" form:"code" binding:"required"`
+	}
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le code est requis"})
+		return
+	}
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	if !user.TwoFactorEnabled {
+		c.JSON(http.StatusConflict, gin.H{"error": "La double authentification n'est pas active"})
+		return
+	}
+
+	var codes []string
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := verifyTOTP(tx, user, input.Code); err != nil {
+			return err
+		}
+		var err error
+		codes, err = generateRecoveryCodes(tx, user.ID)
+		return err
+	})
+	if err != nil {
+		respondSecondFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.RecoveryCodes{RecoveryCodes: codes})
+}
+
+// VerifyTwoFactorLogin godoc
+// @Summary Terminer une connexion avec double authentification
+// @Description Seconde étape de la connexion d'un compte protégé : échange le jeton retourné par /login et un code TOTP (ou un code de secours, qui est alors consommé) contre les jetons d'accès et de rafraîchissement. Après 5 codes invalides, le jeton de connexion est refusé : il faut se reconnecter.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body object{challengeToken=string,code=string,recoveryCode=string} true "Jeton de connexion, et code TOTP ou code de secours"
+// @Success 200 {object} models.TokenPair
+// @Failure 400 {object} map[string]string "Champs manquants"
+// @Failure 401 {object} map[string]string "Jeton de connexion expiré ou code invalide"
+// @Failure 429 {object} map[string]string "Trop de tentatives de connexion"
+// @Router /auth/2fa/verify [post]
+func VerifyTwoFactorLogin(c *gin.Context) {
+	var input struct {
+		ChallengeToken string `json:"challengeToken" form:"challengeToken" binding:"required"`
+		Code           string `json:"code" form:"code"`
+		RecoveryCode   string `json:"recoveryCode" form:"recoveryCode"`
+	}
+	if err := c.ShouldBind(&input); err != nil || (input.Code == "" && input.RecoveryCode == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le jeton de connexion et un code (code ou recoveryCode) sont requis"})
+		return
+	}
+
+	userID, challengeID, err := utils.ParseChallengeToken(input.ChallengeToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Jeton de connexion invalide ou expiré, reconnectez-vous"})
+		return
+	}
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil || !user.TwoFactorEnabled {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Jeton de connexion invalide ou expiré, reconnectez-vous"})
+		return
+	}
+
+	if !checkLoginThrottle(c, user.Email) {
+		return
+	}
+	challengeKey := challengeThrottle().Prefix + challengeID
+	var throttle models.LoginThrottle
+	if err := database.DB.Where("key = ?", challengeKey).First(&throttle).Error; err == nil && !challengeThrottle().blockedUntil(&throttle).IsZero() {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Trop de codes invalides, reconnectez-vous"})
+		return
+	}
+
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return verifySecondFactor(tx, &user, input.Code, input.RecoveryCode)
+	}); err != nil {
+		if errors.Is(err, errSecondFactorInvalid) {
+			recordLoginFailure(c, user.Email, &user)
+			if _, err := challengeThrottle().recordFailure(challengeKey); err != nil {
+				logger.ErrorLogger.Printf("Erreur lors de l'enregistrement d'un échec de connexion (%s) : %v", challengeKey, err)
+			}
+		}
+		respondSecondFactorError(c, err)
+		return
+	}
+	// Les échecs de connexion du compte ne sont oubliés qu'une fois le second facteur vérifié
+	resetLoginFailures(user.Email)
+	database.DB.Where("key = ?", challengeKey).Delete(&models.LoginThrottle{})
+
+	tokens, err := login(c, &user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la génération du token"})
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
@@ -4,16 +4,34 @@ import (
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"travelmate-api/database"
+	"travelmate-api/logger"
 	"travelmate-api/models"
 
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// currentUser charge l'utilisateur connecté depuis la base
+func currentUser(c *gin.Context) (*models.User, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Utilisateur non authentifié"})
+		return nil, false
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Utilisateur non trouvé"})
+		return nil, false
+	}
+	return &user, true
+}
+
 func GetMe(c *gin.Context) {
-	userIDStr, exists := c.Get("userId")
+	userIDStr, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Utilisateur non authentifié"})
 		return
@@ -140,6 +158,7 @@ func UpdateUser(c *gin.Context) {
 	if input.Name != nil {
 		userToUpdate.Name = *input.Name
 	}
+	emailChanged := false
 	if input.Email != nil {
 		var count int64
 		database.DB.Model(&models.User{}).Where("email = ? AND id != ?", *input.Email, userToUpdate.ID).Count(&count)
@@ -147,6 +166,11 @@ func UpdateUser(c *gin.Context) {
 			c.JSON(http.StatusConflict, gin.H{"error": "Cet email est déjà utilisé"})
 			return
 		}
+		// Une nouvelle adresse doit être vérifiée à son tour
+		if !strings.EqualFold(userToUpdate.Email, *input.Email) {
+			emailChanged = true
+			userToUpdate.EmailVerified = false
+		}
 		userToUpdate.Email = *input.Email
 	}
 	if input.Password != nil {
@@ -173,5 +197,13 @@ func UpdateUser(c *gin.Context) {
 		return
 	}
 
+	if emailChanged {
+		if err := sendEmailVerification(&userToUpdate); err != nil {
+			logger.ErrorLogger.Printf("Erreur lors de l'envoi de l'email de vérification (user_id=%d) : %v", userToUpdate.ID, err)
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Utilisateur mis à jour avec succès : un email de vérification a été envoyé à la nouvelle adresse"})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Utilisateur mis à jour avec succès"})
 }
@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"travelmate-api/database"
+	"travelmate-api/mailer"
+	"travelmate-api/models"
+	"travelmate-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Délai minimal entre deux envois de l'email de vérification
+const verificationResendDelay = time.Minute
+
+// errVerificationTokenInvalid signale un jeton de vérification inconnu, expiré, déjà utilisé
+// ou émis pour une autre adresse que celle du compte
+var errVerificationTokenInvalid = errors.New("verification token invalid")
+
+// emailVerificationTTL retourne la durée de validité d'un lien de vérification (EMAIL_VERIFICATION_TTL_HOURS, 48 heures par défaut)
+func emailVerificationTTL() time.Duration {
+	if hours, err := strconv.Atoi(os.Getenv("EMAIL_VERIFICATION_TTL_HOURS")); err == nil && hours > 0 {
+		return time.Duration(hours) * time.Hour
+	}
+	return 48 * time.Hour
+}
+
+// sendEmailVerification envoie un lien de vérification à l'adresse actuelle de l'utilisateur.
+// Les liens envoyés précédemment sont annulés.
+func sendEmailVerification(user *models.User) error {
+	token, err := utils.GenerateRandomToken()
+	if err != nil {
+		return err
+	}
+	verification := models.EmailVerification{
+		UserID:    user.ID,
+		Email:     user.Email,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(emailVerificationTTL()),
+	}
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND used_at IS NULL", user.ID).Delete(&models.EmailVerification{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&verification).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf(
+		"Bonjour %s,\n\nPour confirmer l'adresse email de votre compte TravelMate, ouvrez le lien suivant :\n%s/verify-email?token=%s\n\n"+
+			"Ce lien expire le %s.\nSi vous n'avez pas créé de compte TravelMate, ignorez cet email.",
+		user.Name, appURL(), token, verification.ExpiresAt.Format("02/01/2006 à 15:04"),
+	)
+	return mailer.Send(mailer.Message{To: user.Email, Subject: "Confirmez votre adresse email", Body: body})
+}
+
+// VerifyEmail godoc
+// @Summary Vérifier l'adresse email
+// @Description Confirme l'adresse email du compte à l'aide du jeton reçu par email. Ne nécessite pas d'être connecté. Le lien n'est plus valable si l'adresse du compte a changé depuis son envoi.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body object{token=string} true "Jeton reçu par email"
+// @Success 200 {object} map[string]string "Adresse email vérifiée"
+// @Failure 400 {object} map[string]string "Jeton manquant, invalide ou expiré"
+// @Router /auth/verify-email [post]
+func VerifyEmail(c *gin.Context) {
+	var input struct {
+		Token string `json:"token" form:"token" binding:"required"`
+	}
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Le jeton de vérification est requis"})
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var verification models.EmailVerification
+		if err := tx.Where("token_hash = ?", utils.HashToken(input.Token)).First(&verification).Error; err != nil {
+			return errVerificationTokenInvalid
+		}
+		if verification.UsedAt != nil || time.Now().After(verification.ExpiresAt) {
+			return errVerificationTokenInvalid
+		}
+		var user models.User
+		if err := tx.First(&user, verification.UserID).Error; err != nil || !strings.EqualFold(user.Email, verification.Email) {
+			return errVerificationTokenInvalid
+		}
+
+		if err := tx.Model(&verification).Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Model(&user).Update("email_verified", true).Error
+	})
+	if errors.Is(err, errVerificationTokenInvalid) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Lien de vérification invalide ou expiré"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la vérification de l'adresse email"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Adresse email vérifiée"})
+}
+
+// ResendEmailVerification godoc
+// @Summary Renvoyer l'email de vérification
+// @Description Envoie un nouveau lien de vérification à l'adresse email de l'utilisateur connecté ; les liens précédents ne sont plus valables.
+// @Tags auth
+// @Produce json
+// @Success 202 {object} map[string]string "Email envoyé"
+// @Failure 409 {object} map[string]string "Adresse email déjà vérifiée"
+// @Failure 429 {object} map[string]string "Email envoyé il y a moins d'une minute"
+// @Security BearerAuth
+// @Router /auth/resend-verification [post]
+func ResendEmailVerification(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	if user.EmailVerified {
+		c.JSON(http.StatusConflict, gin.H{"error": "Adresse email déjà vérifiée"})
+		return
+	}
+
+	var last models.EmailVerification
+	err := database.DB.Where("user_id = ?", user.ID).Order("created_at DESC").First(&last).Error
+	if err == nil && time.Since(last.CreatedAt) < verificationResendDelay {
+		c.Header("Retry-After", strconv.Itoa(int((verificationResendDelay-time.Since(last.CreatedAt)).Seconds())+1))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Un email de vérification vient d'être envoyé, réessayez dans une minute"})
+		return
+	}
+
+	if err := sendEmailVerification(user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de l'envoi de l'email de vérification"})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "Un email de vérification a été envoyé à " + user.Email})
+}
@@ -19,8 +19,16 @@ func InitDB() error {
 		return err
 	}
 
-	DB.AutoMigrate(&models.User{}, models.Trip{})
+	dropRefreshTokenFamilies()
+	// Les comptes créés avant la vérification des emails sont considérés comme vérifiés
+	verifyExistingUsers := DB.Migrator().HasTable(&models.User{}) && !DB.Migrator().HasColumn(&models.User{}, "email_verified")
+	DB.AutoMigrate(&models.User{}, &models.Session{}, &models.RefreshToken{}, &models.RevokedToken{}, &models.PasswordReset{}, &models.EmailVerification{}, &models.RecoveryCode{}, &models.APIToken{}, &models.OIDCLogin{}, &models.UserIdentity{}, &models.LoginThrottle{}, &models.AuditEvent{}, models.Trip{}, &models.TripMember{}, &models.Invitation{}, &models.ShareLink{}, &models.CalendarFeed{}, &models.ItineraryDay{}, &models.Stop{}, &models.Expense{}, &models.ExpenseSplit{}, &models.Settlement{}, &models.Budget{}, &models.BudgetCategory{})
+	if verifyExistingUsers {
+		DB.Model(&models.User{}).Where("1 = 1").Update("email_verified", true)
+	}
 	createDefaultAdmin()
+	backfillTripOwners()
+	setupTripSearch()
 
 	log.Println("db init")
 
@@ -34,10 +42,11 @@ func createDefaultAdmin() {
 	if count == 0 {
 		hashedPassword, _ := utils.HashPassword("admin123456")
 		admin := models.User{
-			Name:     "Admin",
-			Email:    "admin@travelmate.com",
-			Password: hashedPassword,
-			IsAdmin:  true,
+			Name:          "Admin",
+			Email:         "admin@travelmate.com",
+			Password:      hashedPassword,
+			IsAdmin:       true,
+			EmailVerified: true,
 		}
 
 		DB.Create(&admin)
@@ -45,3 +54,24 @@ func createDefaultAdmin() {
 	}
 }
 
+// Les jetons de rafraîchissement étaient regroupés par famille avant l'introduction des
+// sessions. Ils ne sont rattachés à aucune session : la table est recréée, ce qui
+// déconnecte les utilisateurs concernés.
+func dropRefreshTokenFamilies() {
+	if DB.Migrator().HasColumn(&models.RefreshToken{}, "family_id") {
+		DB.Migrator().DropTable(&models.RefreshToken{})
+	}
+}
+
+// Les voyages créés avant le partage n'ont pas de membre propriétaire
+func backfillTripOwners() {
+	var trips []models.Trip
+	DB.Where("user_id <> 0 AND id NOT IN (?)", DB.Model(&models.TripMember{}).Select("trip_id").Where("role = ?", models.RoleOwner)).Find(&trips)
+
+	for _, trip := range trips {
+		DB.Omit("User").Create(&models.TripMember{TripID: trip.ID, UserID: trip.UserID, Role: models.RoleOwner})
+	}
+	if len(trips) > 0 {
+		log.Printf("%d propriétaire(s) de voyage ajouté(s) comme membre(s).", len(trips))
+	}
+}
@@ -0,0 +1,51 @@
+package database
+
+import "log"
+
+// setupTripSearch crée l'index plein texte des voyages (FTS5) et les triggers qui le
+// maintiennent à jour. L'index ne stocke pas le contenu, il le relit dans la table trips.
+// Les accents sont ignorés (remove_diacritics) et les préfixes de 2 et 3 lettres indexés
+// pour accélérer la recherche pendant la saisie.
+func setupTripSearch() {
+	var count int64
+	DB.Raw("SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = 'trips_fts'").Scan(&count)
+	created := count == 0
+
+	statements := []string{
+		`CREATE VIRTUAL TABLE IF NOT EXISTS trips_fts USING fts5(
+			title, description, location, notes,
+			content='trips', content_rowid='id',
+			tokenize='unicode61 remove_diacritics 2',
+			prefix='2 3'
+		)`,
+		`CREATE TRIGGER IF NOT EXISTS trips_fts_insert AFTER INSERT ON trips BEGIN
+			INSERT INTO trips_fts(rowid, title, description, location, notes)
+			VALUES (new.id, new.title, new.description, new.location, new.notes);
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS trips_fts_delete AFTER DELETE ON trips BEGIN
+			INSERT INTO trips_fts(trips_fts, rowid, title, description, location, notes)
+			VALUES ('delete', old.id, old.title, old.description, old.location, old.notes);
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS trips_fts_update AFTER UPDATE ON trips BEGIN
+			INSERT INTO trips_fts(trips_fts, rowid, title, description, location, notes)
+			VALUES ('delete', old.id, old.title, old.description, old.location, old.notes);
+			INSERT INTO trips_fts(rowid, title, description, location, notes)
+			VALUES (new.id, new.title, new.description, new.location, new.notes);
+		END`,
+	}
+	for _, statement := range statements {
+		if err := DB.Exec(statement).Error; err != nil {
+			log.Printf("Erreur lors de la création de l'index de recherche : %v", err)
+			return
+		}
+	}
+
+	// Les voyages existants sont indexés à la création de l'index
+	if created {
+		if err := DB.Exec("INSERT INTO trips_fts(trips_fts) VALUES ('rebuild')").Error; err != nil {
+			log.Printf("Erreur lors de l'indexation des voyages : %v", err)
+			return
+		}
+		log.Println("Index de recherche des voyages créé.")
+	}
+}
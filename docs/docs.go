@@ -15,70 +15,54 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/login": {
-            "post": {
-                "description": "Permet à un utilisateur de s'authentifier",
-                "consumes": [
-                    "application/x-www-form-urlencoded"
-                ],
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "Retourne les clés publiques (JWKS, RFC 7517) permettant de vérifier les jetons émis par l'API, désignées par l'en-tête kid des jetons : la clé courante et les clés remplacées dont les jetons peuvent encore être valides.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "auth"
                 ],
-                "summary": "Authentification d'un utilisateur",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Email",
-                        "name": "email",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Mot de passe",
-                        "name": "password",
-                        "in": "formData",
-                        "required": true
-                    }
-                ],
+                "summary": "Clés publiques de signature des jetons",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Jeu de clés JWKS",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.RegisterResponse"
-                            }
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
             }
         },
-        "/register": {
-            "post": {
-                "description": "Permet à un utilisateur de créer un compte",
-                "consumes": [
-                    "application/x-www-form-urlencoded"
+        "/admin/audit-events": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Retourne les derniers événements de sécurité (verrouillages et déverrouillages de comptes...), du plus récent au plus ancien. Réservé aux administrateurs.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "Admin"
                 ],
-                "summary": "Création d'un utilisateur",
+                "summary": "Événements d'audit (admin)",
                 "parameters": [
                     {
-                        "description": "Nom de l'utilisateur",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Register"
-                        }
+                        "type": "string",
+                        "description": "Type d'événement (login.locked, login.ip_locked, login.unlocked)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de l'utilisateur concerné",
+                        "name": "userId",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -87,96 +71,100 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.RegisterResponse"
+                                "$ref": "#/definitions/models.AuditEvent"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Accès réservé aux administrateurs",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     }
                 }
             }
         },
-        "/trips": {
+        "/admin/users/{id}/sessions": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retourne tous les voyages enregistrés",
+                "description": "Retourne les sessions actives d'un utilisateur. Réservé aux administrateurs.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "trips"
+                    "Admin"
+                ],
+                "summary": "Sessions d'un utilisateur (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Liste tous les voyages",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Trip"
+                                "$ref": "#/definitions/models.Session"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Accès réservé aux administrateurs",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     }
                 }
             },
-            "put": {
-                "description": "Met à jour les champs spécifiés pour une liste de voyages",
-                "consumes": [
-                    "application/json"
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Déconnecte l'utilisateur de tous ses appareils. Réservé aux administrateurs.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Trips"
+                    "Admin"
                 ],
-                "summary": "Mettre à jour plusieurs voyages",
+                "summary": "Révoquer toutes les sessions d'un utilisateur (admin)",
                 "parameters": [
                     {
-                        "description": "Liste des IDs et des champs à mettre à jour",
-                        "name": "update",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "properties": {
-                                "ids": {
-                                    "type": "array",
-                                    "items": {
-                                        "type": "integer"
-                                    }
-                                },
-                                "update": {
-                                    "type": "object",
-                                    "additionalProperties": true
-                                }
-                            }
-                        }
+                        "type": "integer",
+                        "description": "ID de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Mise à jour effectuée",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Format invalide ou données manquantes",
+                        "description": "Nombre de sessions révoquées",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
-                    "500": {
-                        "description": "Erreur lors de la mise à jour",
+                    "403": {
+                        "description": "Accès réservé aux administrateurs",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -185,39 +173,51 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Crée un nouveau voyage avec les informations fournies",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/admin/users/{id}/sessions/{session}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Déconnecte un appareil d'un utilisateur. Réservé aux administrateurs.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Trips"
+                    "Admin"
                 ],
-                "summary": "Créer un voyage",
+                "summary": "Révoquer une session d'un utilisateur (admin)",
                 "parameters": [
                     {
-                        "description": "Données du voyage",
-                        "name": "trip",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Trip"
-                        }
+                        "type": "integer",
+                        "description": "ID de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de la session",
+                        "name": "session",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "La session a bien été révoquée",
                         "schema": {
-                            "$ref": "#/definitions/models.Trip"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Format invalide",
+                    "403": {
+                        "description": "Accès réservé aux administrateurs",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -225,8 +225,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Erreur de création",
+                    "404": {
+                        "description": "Session non trouvée",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -237,40 +237,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/trips/search": {
-            "get": {
-                "description": "Recherche les voyages dont un champ contient la sous-chaîne donnée (titre, description, localisation, dates)",
-                "consumes": [
-                    "application/json"
+        "/admin/users/{id}/unlock": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Efface les échecs de connexion d'un utilisateur : son compte est déverrouillé immédiatement. Le blocage éventuel de son adresse IP n'est pas levé. Réservé aux administrateurs.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Trips"
+                    "Admin"
                 ],
-                "summary": "Rechercher des voyages",
+                "summary": "Déverrouiller un compte (admin)",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Terme de recherche (doit correspondre partiellement à un champ du voyage)",
-                        "name": "query",
-                        "in": "query",
+                        "type": "integer",
+                        "description": "ID de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Compte déverrouillé",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Trip"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Le paramètre 'query' est requis",
+                    "403": {
+                        "description": "Accès réservé aux administrateurs",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -278,8 +280,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Erreur lors de la recherche",
+                    "404": {
+                        "description": "Utilisateur non trouvé",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -290,37 +292,68 @@ const docTemplate = `{
                 }
             }
         },
-        "/trips/user/{id}": {
-            "get": {
-                "description": "Retourne tous les voyages associés à un utilisateur donné",
+        "/auth/2fa/verify": {
+            "post": {
+                "description": "Seconde étape de la connexion d'un compte protégé : échange le jeton retourné par /login et un code TOTP (ou un code de secours, qui est alors consommé) contre les jetons d'accès et de rafraîchissement. Après 5 codes invalides, le jeton de connexion est refusé : il faut se reconnecter.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Trips"
+                    "auth"
                 ],
-                "summary": "Récupérer les voyages d’un utilisateur",
+                "summary": "Terminer une connexion avec double authentification",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID de l'utilisateur",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Jeton de connexion, et code TOTP ou code de secours",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "challengeToken": {
+                                    "type": "string"
+                                },
+                                "code": {
+                                    "type": "string"
+                                },
+                                "recoveryCode": {
+                                    "type": "string"
+                                }
+                            }
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Trip"
+                            "$ref": "#/definitions/models.TokenPair"
+                        }
+                    },
+                    "400": {
+                        "description": "Champs manquants",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
-                    "500": {
-                        "description": "Erreur lors de la récupération des voyages",
+                    "401": {
+                        "description": "Jeton de connexion expiré ou code invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Trop de tentatives de connexion",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -331,34 +364,47 @@ const docTemplate = `{
                 }
             }
         },
-        "/trips/{id}": {
-            "get": {
-                "description": "Retourne les détails d’un voyage spécifique à partir de son ID",
+        "/auth/forgot-password": {
+            "post": {
+                "description": "Envoie par email un lien de réinitialisation du mot de passe, à usage unique et limité dans le temps. La réponse est la même que l'adresse corresponde ou non à un compte. Une seule demande par minute est traitée pour une même adresse email ou une même adresse IP : les suivantes sont ignorées, avec la même réponse.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Trips"
+                    "auth"
                 ],
-                "summary": "Récupérer un voyage par son ID",
+                "summary": "Mot de passe oublié",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID du voyage",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Email du compte",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "email": {
+                                    "type": "string"
+                                }
+                            }
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Demande prise en compte",
                         "schema": {
-                            "$ref": "#/definitions/models.Trip"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Voyage non trouvé",
+                    "400": {
+                        "description": "Email manquant ou invalide",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -367,46 +413,26 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Met à jour un voyage existant avec les nouvelles données fournies",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Révoque immédiatement le jeton d'accès utilisé et la session dont il est issu, avec ses jetons de rafraîchissement. Les autres appareils restent connectés.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Trips"
-                ],
-                "summary": "Mettre à jour un voyage",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID du voyage",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Nouvelles données du voyage",
-                        "name": "trip",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Trip"
-                        }
-                    }
+                    "auth"
                 ],
+                "summary": "Déconnexion",
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.Trip"
-                        }
-                    },
-                    "400": {
-                        "description": "Format invalide",
+                        "description": "Déconnexion effectuée",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -414,8 +440,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Voyage non trouvé",
+                    "401": {
+                        "description": "Non authentifié",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -424,42 +450,93 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/auth/logout-all": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Supprime un voyage par son ID si l'utilisateur est le propriétaire ou un administrateur",
+                "description": "Révoque toutes les sessions de l'utilisateur, avec leurs jetons d'accès et de rafraîchissement, y compris la session utilisée.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Trips"
+                    "auth"
                 ],
-                "summary": "Supprimer un voyage",
+                "summary": "Déconnexion de tous les appareils",
+                "responses": {
+                    "200": {
+                        "description": "Déconnexion effectuée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Non authentifié",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/{provider}/callback": {
+            "get": {
+                "description": "Termine la connexion par un fournisseur d'identité, dans le navigateur qui l'a commencée (cookie tm_oidc_login) : vérifie le jeton d'identité et retourne les jetons d'accès et de rafraîchissement. Une identité inconnue est reliée au compte de même adresse email, ou à un nouveau compte, si le fournisseur a vérifié cette adresse. Si la double authentification est active, retourne un jeton de connexion à utiliser avec POST /auth/2fa/verify.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Retour du fournisseur d'identité",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID du voyage à supprimer",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Nom du fournisseur (OIDC_PROVIDERS)",
+                        "name": "provider",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Code d'autorisation",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "État de la connexion",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Le voyage a bien été supprimé",
+                        "description": "Connexion établie",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.TokenPair"
+                        }
+                    },
+                    "202": {
+                        "description": "Double authentification requise",
+                        "schema": {
+                            "$ref": "#/definitions/models.TwoFactorChallenge"
                         }
                     },
                     "400": {
-                        "description": "Requête invalide",
+                        "description": "Connexion invalide, expirée ou commencée dans un autre navigateur",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -468,7 +545,7 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Utilisateur non authentifié",
+                        "description": "Connexion refusée par le fournisseur d'identité",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -477,7 +554,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Accès refusé",
+                        "description": "Adresse email non vérifiée par le fournisseur",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -486,7 +563,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Voyage introuvable",
+                        "description": "Fournisseur d'identité inconnu",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -494,8 +571,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Erreur serveur lors de la suppression",
+                    "409": {
+                        "description": "Compte existant dont l'adresse email n'est pas vérifiée",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -506,137 +583,5688 @@ const docTemplate = `{
                 }
             }
         },
-        "/user": {
+        "/auth/oidc/{provider}/login": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Retourne tous les utilisateurs enregistrés",
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Redirige vers la page de connexion du fournisseur d'identité OpenID Connect (code d'autorisation avec PKCE), et place un cookie tm_oidc_login reliant la connexion au navigateur. Après connexion, le fournisseur redirige vers /auth/oidc/{provider}/callback, ou vers l'adresse de retour configurée pour le fournisseur (OIDC_\u003cNOM\u003e_REDIRECT_URL), qui doit alors transmettre les paramètres code et state à cette route.",
                 "tags": [
-                    "users"
+                    "auth"
                 ],
-                "summary": "Liste un utilisateur en fonction de son email",
+                "summary": "Connexion par un fournisseur d'identité",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Email de l'utilisateur",
-                        "name": "email",
+                        "description": "Nom du fournisseur (OIDC_PROVIDERS)",
+                        "name": "provider",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "302": {
+                        "description": "Redirection vers le fournisseur d'identité"
+                    },
+                    "404": {
+                        "description": "Fournisseur d'identité inconnu",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.User"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "502": {
+                        "description": "Fournisseur d'identité indisponible",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     }
                 }
             }
         },
-        "/users": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+        "/auth/refresh": {
+            "post": {
+                "description": "Échange un jeton de rafraîchissement contre un nouveau jeton d'accès et un nouveau jeton de rafraîchissement ; l'ancien ne peut plus être utilisé. Présenter un jeton déjà échangé révoque toute la session, qui a pu être compromise.",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Retourne tous les utilisateurs enregistrés",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "auth"
+                ],
+                "summary": "Renouveler le jeton d'accès",
+                "parameters": [
+                    {
+                        "description": "Jeton de rafraîchissement",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "refreshToken": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
                 ],
-                "summary": "Liste tous les utilisateurs",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.TokenPair"
+                        }
+                    },
+                    "400": {
+                        "description": "Jeton manquant",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Jeton invalide, expiré, révoqué ou réutilisé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/auth/resend-verification": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Met à jour un utilisateur",
+                "description": "Envoie un nouveau lien de vérification à l'adresse email de l'utilisateur connecté ; les liens précédents ne sont plus valables.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
-                ],
-                "summary": "Mise à jour d'un utilisateur",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Identifiant de l'utilisateur",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "auth"
                 ],
+                "summary": "Renvoyer l'email de vérification",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Email envoyé",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.User"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Adresse email déjà vérifiée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Email envoyé il y a moins d'une minute",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.Register": {
-            "type": "object",
-            "required": [
-                "email",
-                "name",
-                "password"
-            ],
+        },
+        "/auth/reset-password": {
+            "post": {
+                "description": "Définit un nouveau mot de passe à l'aide du jeton reçu par email. Le jeton ne peut plus être réutilisé et toutes les sessions de l'utilisateur sont révoquées.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Réinitialiser le mot de passe",
+                "parameters": [
+                    {
+                        "description": "Jeton reçu par email et nouveau mot de passe (6 caractères minimum)",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "password": {
+                                    "type": "string"
+                                },
+                                "token": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Mot de passe modifié",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Champs invalides, ou lien invalide ou expiré",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/verify-email": {
+            "post": {
+                "description": "Confirme l'adresse email du compte à l'aide du jeton reçu par email. Ne nécessite pas d'être connecté. Le lien n'est plus valable si l'adresse du compte a changé depuis son envoi.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Vérifier l'adresse email",
+                "parameters": [
+                    {
+                        "description": "Jeton reçu par email",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "token": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Adresse email vérifiée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Jeton manquant, invalide ou expiré",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/calendar/{token}": {
+            "get": {
+                "description": "Retourne, sans authentification, les voyages dont l'utilisateur est propriétaire ou membre au format iCalendar. L'adresse est celle retournée par POST /me/calendar-feed.",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "Calendar"
+                ],
+                "summary": "Flux iCalendar d'un utilisateur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Jeton du flux, suivi de .ics",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Calendrier iCalendar",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Flux introuvable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/invitations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne les invitations en attente et non expirées adressées à l'email de l'utilisateur connecté",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invitations"
+                ],
+                "summary": "Mes invitations",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Invitation"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/invitations/accept": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Vérifie le jeton signé reçu par email et accepte l'invitation correspondante",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invitations"
+                ],
+                "summary": "Accepter une invitation depuis le lien reçu par email",
+                "parameters": [
+                    {
+                        "description": "Jeton d'invitation",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "token": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Invitation"
+                        }
+                    },
+                    "400": {
+                        "description": "Jeton d'invitation invalide ou expiré",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Cette invitation ne vous est pas adressée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Cette invitation a déjà été traitée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/invitations/{invitation}/accept": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Accepte une invitation adressée à l'utilisateur connecté et le rend membre du voyage",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invitations"
+                ],
+                "summary": "Accepter une invitation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'invitation",
+                        "name": "invitation",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Invitation"
+                        }
+                    },
+                    "403": {
+                        "description": "Cette invitation ne vous est pas adressée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Invitation non trouvée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Cette invitation a déjà été traitée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "410": {
+                        "description": "Cette invitation a expiré",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/invitations/{invitation}/decline": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Refuse une invitation adressée à l'utilisateur connecté",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invitations"
+                ],
+                "summary": "Refuser une invitation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'invitation",
+                        "name": "invitation",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Invitation"
+                        }
+                    },
+                    "403": {
+                        "description": "Cette invitation ne vous est pas adressée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Invitation non trouvée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Cette invitation a déjà été traitée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "Permet à un utilisateur de s'authentifier. Après plusieurs échecs pour un même compte ou une même adresse IP, les tentatives sont retardées puis temporairement bloquées.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Authentification d'un utilisateur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Email",
+                        "name": "email",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mot de passe",
+                        "name": "password",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Connexion établie",
+                        "schema": {
+                            "$ref": "#/definitions/models.TokenPair"
+                        }
+                    },
+                    "202": {
+                        "description": "Double authentification requise",
+                        "schema": {
+                            "$ref": "#/definitions/models.TwoFactorChallenge"
+                        }
+                    },
+                    "401": {
+                        "description": "Identifiants invalides",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Trop de tentatives de connexion",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/me/2fa": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Désactive la double authentification après vérification du mot de passe et d'un code (TOTP ou de secours). Impossible pour un administrateur si elle est obligatoire.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "2FA"
+                ],
+                "summary": "Désactiver la double authentification",
+                "parameters": [
+                    {
+                        "description": "Mot de passe, et code TOTP ou code de secours",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "string"
+                                },
+                                "password": {
+                                    "type": "string"
+                                },
+                                "recoveryCode": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Double authentification désactivée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Champs manquants",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Mot de passe ou code invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Double authentification obligatoire",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Double authentification inactive",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/me/2fa/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Active la double authentification à l'aide d'un premier code généré par l'application, et retourne les codes de secours à usage unique. Ces codes ne sont affichés qu'une fois.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "2FA"
+                ],
+                "summary": "Confirmer la double authentification",
+                "parameters": [
+                    {
+                        "description": "Code à 6 chiffres",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecoveryCodes"
+                        }
+                    },
+                    "400": {
+                        "description": "Code manquant ou activation non commencée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Code invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Double authentification déjà active",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/me/2fa/recovery-codes": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remplace les codes de secours après vérification d'un code TOTP. Les anciens codes ne sont plus valables.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "2FA"
+                ],
+                "summary": "Renouveler les codes de secours",
+                "parameters": [
+                    {
+                        "description": "Code à 6 chiffres",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecoveryCodes"
+                        }
+                    },
+                    "401": {
+                        "description": "Code invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Double authentification inactive",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/me/2fa/setup": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Génère un secret TOTP (RFC 6238) à enregistrer dans une application d'authentification, sous forme d'URI otpauth:// et de QR code PNG. La double authentification n'est active qu'après confirmation avec POST /me/2fa/confirm ; relancer l'activation remplace le secret non confirmé.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "2FA"
+                ],
+                "summary": "Activer la double authentification",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TwoFactorSetup"
+                        }
+                    },
+                    "409": {
+                        "description": "Double authentification déjà active",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/me/calendar-feed": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Crée l'adresse secrète du flux iCalendar de l'utilisateur, à laquelle une application de calendrier peut s'abonner sans authentification. Un nouvel appel remplace l'adresse précédente, qui cesse de fonctionner. Le jeton n'est retourné qu'une seule fois.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Calendar"
+                ],
+                "summary": "Créer le flux iCalendar personnel",
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.CalendarFeed"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur lors de la création du flux",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Désactive l'adresse du flux iCalendar de l'utilisateur.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Calendar"
+                ],
+                "summary": "Supprimer le flux iCalendar personnel",
+                "responses": {
+                    "200": {
+                        "description": "Le flux a bien été supprimé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Aucun flux actif",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/me/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne les appareils sur lesquels l'utilisateur est connecté : navigateur (User-Agent), adresse IP, date de connexion et de dernière activité. La session utilisée pour la requête est marquée current.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sessions"
+                ],
+                "summary": "Mes sessions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Session"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Non authentifié",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/me/sessions/{session}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Déconnecte un appareil (téléphone perdu...) : ses jetons d'accès et de rafraîchissement sont refusés immédiatement.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sessions"
+                ],
+                "summary": "Révoquer une de mes sessions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la session",
+                        "name": "session",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "La session a bien été révoquée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Session non trouvée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/me/tokens": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne les jetons d'accès personnels actifs (ni révoqués ni expirés) de l'utilisateur, sans leur valeur, avec leur date de dernière utilisation.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API tokens"
+                ],
+                "summary": "Mes jetons d'API",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.APIToken"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Action indisponible avec un jeton d'API",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Crée un jeton d'accès personnel pour un script ou une intégration, à utiliser comme un jeton d'accès (Authorization: Bearer tm_pat_...). Il ne donne accès qu'aux routes couvertes par ses scopes (trips:read, trips:write, users:read), jamais à la gestion du compte ni à l'administration. Sans date d'expiration, il expire au bout de 90 jours ; au plus tard au bout d'un an. Le jeton n'est retourné qu'une seule fois.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API tokens"
+                ],
+                "summary": "Créer un jeton d'API",
+                "parameters": [
+                    {
+                        "description": "Nom, scopes et date d'expiration",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "expiresAt": {
+                                    "type": "string"
+                                },
+                                "name": {
+                                    "type": "string"
+                                },
+                                "scopes": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "string"
+                                    }
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIToken"
+                        }
+                    },
+                    "400": {
+                        "description": "Nom, scopes ou date d'expiration invalides",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Action indisponible avec un jeton d'API",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/me/tokens/{token}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Révoque un jeton d'accès personnel : il est refusé immédiatement.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API tokens"
+                ],
+                "summary": "Révoquer un jeton d'API",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du jeton",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Le token a bien été révoqué",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Action indisponible avec un jeton d'API",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Token non trouvé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/register": {
+            "post": {
+                "description": "Permet à un utilisateur de créer un compte",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Création d'un utilisateur",
+                "parameters": [
+                    {
+                        "description": "Nom de l'utilisateur",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Register"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RegisterResponse"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/shared/{token}": {
+            "get": {
+                "description": "Retourne la vue publique d'un voyage à partir d'un lien de partage, sans authentification. Les notes et l'identité du propriétaire ne sont visibles que si le lien le prévoit.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sharing"
+                ],
+                "summary": "Consulter un voyage partagé",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Jeton du lien de partage",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SharedTrip"
+                        }
+                    },
+                    "404": {
+                        "description": "Lien invalide, expiré ou révoqué",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne les voyages dont l'utilisateur connecté est propriétaire ou membre. Un administrateur peut demander ceux d'un utilisateur (userId) ou tous les voyages (all=true).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trips"
+                ],
+                "summary": "Liste les voyages",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'utilisateur (administrateurs uniquement)",
+                        "name": "userId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Tous les voyages (administrateurs uniquement)",
+                        "name": "all",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Numéro de page (1 par défaut)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Nombre de voyages par page (20 par défaut, 100 au maximum)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tri : startDate, title ou createdAt, préfixé de - pour un ordre décroissant",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Voyages se terminant à partir de cette date (AAAA-MM-JJ)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Voyages commençant au plus tard à cette date (AAAA-MM-JJ)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Localisation (correspondance partielle)",
+                        "name": "location",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Statut du voyage (upcoming, ongoing ou past)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Trip"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Liens vers les pages first, prev, next et last"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Nombre total de voyages correspondants"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Paramètre invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Met à jour les champs spécifiés pour une liste de voyages : title, description, location, startDate, endDate, latitude, longitude et notes ; tout autre champ est refusé. Seuls les voyages sur lesquels l'utilisateur a le rôle editor ou owner sont modifiés, et les dates d'un voyage ne sont pas modifiées si elles excluent des journées de son programme ; le résultat est détaillé pour chaque ID (updated, forbidden, not_found ou conflict).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trips"
+                ],
+                "summary": "Mettre à jour plusieurs voyages",
+                "parameters": [
+                    {
+                        "description": "Liste des IDs et des champs à mettre à jour",
+                        "name": "update",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "ids": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "integer"
+                                    }
+                                },
+                                "update": {
+                                    "type": "object",
+                                    "additionalProperties": true
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tous les voyages ont été mis à jour",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "processed": {
+                                    "type": "integer"
+                                },
+                                "results": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.BulkTripResult"
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    "207": {
+                        "description": "Une partie des voyages a été mise à jour",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "processed": {
+                                    "type": "integer"
+                                },
+                                "results": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.BulkTripResult"
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Format invalide, données manquantes ou coordonnées invalides",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Aucun voyage n'a pu être mis à jour",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "processed": {
+                                    "type": "integer"
+                                },
+                                "results": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.BulkTripResult"
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur lors de la mise à jour",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Crée un nouveau voyage avec les informations fournies. Le voyage appartient toujours à l'utilisateur connecté.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trips"
+                ],
+                "summary": "Créer un voyage",
+                "parameters": [
+                    {
+                        "description": "Données du voyage",
+                        "name": "trip",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Trip"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Trip"
+                        }
+                    },
+                    "400": {
+                        "description": "Format invalide ou coordonnées invalides",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur de création",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Supprime une liste de voyages. Seuls les voyages dont l'utilisateur est propriétaire (rôle owner) sont supprimés ; le résultat est détaillé pour chaque ID (deleted, forbidden ou not_found).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trips"
+                ],
+                "summary": "Supprimer plusieurs voyages",
+                "parameters": [
+                    {
+                        "description": "Liste des IDs à supprimer",
+                        "name": "ids",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "ids": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "integer"
+                                    }
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tous les voyages ont été supprimés",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "processed": {
+                                    "type": "integer"
+                                },
+                                "results": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.BulkTripResult"
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    "207": {
+                        "description": "Une partie des voyages a été supprimée",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "processed": {
+                                    "type": "integer"
+                                },
+                                "results": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.BulkTripResult"
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Format invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Aucun voyage n'a pu être supprimé",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "processed": {
+                                    "type": "integer"
+                                },
+                                "results": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.BulkTripResult"
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur lors de la suppression",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips.geojson": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne les voyages localisés visibles par l'utilisateur sous forme de FeatureCollection GeoJSON (RFC 7946), avec leur titre, leurs dates et leur statut. Avec zoom, les voyages proches à ce niveau de zoom sont regroupés en un seul point (propriétés cluster et pointCount, bbox du groupe) ; au-delà du zoom 16, ils ne sont plus regroupés.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trips"
+                ],
+                "summary": "Voyages au format GeoJSON",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rectangle minLng,minLat,maxLng,maxLat",
+                        "name": "bbox",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Niveau de zoom de la carte (0 à 22) pour le regroupement",
+                        "name": "zoom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de l'utilisateur (administrateurs uniquement)",
+                        "name": "userId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Tous les voyages (administrateurs uniquement)",
+                        "name": "all",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Voyages se terminant à partir de cette date (AAAA-MM-JJ)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Voyages commençant au plus tard à cette date (AAAA-MM-JJ)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Localisation (correspondance partielle)",
+                        "name": "location",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Statut du voyage (upcoming, ongoing ou past)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/geo.FeatureCollection"
+                        }
+                    },
+                    "400": {
+                        "description": "Paramètre invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne les voyages visibles par l'utilisateur dans un fichier CSV (UTF-8) ou un classeur XLSX, une ligne par voyage. Les colonnes (title, description, location, startDate, endDate, latitude, longitude, notes) sont celles attendues par l'import. Les filtres de la liste des voyages s'appliquent.",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Export"
+                ],
+                "summary": "Exporter les voyages en CSV ou XLSX",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Format du fichier (csv par défaut, ou xlsx)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de l'utilisateur (administrateurs uniquement)",
+                        "name": "userId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Tous les voyages (administrateurs uniquement)",
+                        "name": "all",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Voyages se terminant à partir de cette date (AAAA-MM-JJ)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Voyages commençant au plus tard à cette date (AAAA-MM-JJ)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Localisation (correspondance partielle)",
+                        "name": "location",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Statut du voyage (upcoming, ongoing ou past)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Fichier CSV ou XLSX",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Paramètre invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/export.gpx": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne un fichier GPX 1.1 avec un waypoint par voyage et par étape localisés, une route reliant les voyages par ordre chronologique et une route par voyage reliant ses étapes. Les filtres de la liste des voyages s'appliquent.",
+                "produces": [
+                    "application/gpx+xml"
+                ],
+                "tags": [
+                    "Export"
+                ],
+                "summary": "Exporter les voyages au format GPX",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'utilisateur (administrateurs uniquement)",
+                        "name": "userId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Tous les voyages (administrateurs uniquement)",
+                        "name": "all",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Voyages se terminant à partir de cette date (AAAA-MM-JJ)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Voyages commençant au plus tard à cette date (AAAA-MM-JJ)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Localisation (correspondance partielle)",
+                        "name": "location",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Statut du voyage (upcoming, ongoing ou past)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Fichier GPX",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Paramètre invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/export.kml": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne un fichier KML 2.2 (Google Earth) avec un repère daté par voyage et par étape localisés, une ligne reliant les voyages par ordre chronologique et une ligne par voyage reliant ses étapes. Les filtres de la liste des voyages s'appliquent.",
+                "produces": [
+                    "application/vnd.google-earth.kml+xml"
+                ],
+                "tags": [
+                    "Export"
+                ],
+                "summary": "Exporter les voyages au format KML",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'utilisateur (administrateurs uniquement)",
+                        "name": "userId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Tous les voyages (administrateurs uniquement)",
+                        "name": "all",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Voyages se terminant à partir de cette date (AAAA-MM-JJ)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Voyages commençant au plus tard à cette date (AAAA-MM-JJ)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Localisation (correspondance partielle)",
+                        "name": "location",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Statut du voyage (upcoming, ongoing ou past)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Fichier KML",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Paramètre invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Crée un voyage par ligne du fichier (la première ligne contient les noms des colonnes). Les colonnes sont associées aux champs du voyage par leur nom, ou selon la correspondance fournie dans mapping (objet JSON nom de colonne → champ). Les dates sont acceptées aux formats AAAA-MM-JJ et JJ/MM/AAAA. Chaque ligne invalide est signalée avec son numéro et la raison. En mode atomic (par défaut), rien n'est créé si une ligne est invalide ; en mode partial, seules les lignes valides sont importées. Les doublons (même titre et mêmes dates qu'un voyage existant) ne sont pas créés.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Import"
+                ],
+                "summary": "Importer des voyages depuis un fichier CSV ou XLSX",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Fichier .csv ou .xlsx (5 Mo au maximum)",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Correspondance des colonnes, par exemple {\\",
+                        "name": "mapping",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Format du fichier (csv ou xlsx), déduit de l'extension par défaut",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "atomic (par défaut) ou partial",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Simulation, sans création",
+                        "name": "dryRun",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Simulation, ou aucun voyage créé",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportReport"
+                        }
+                    },
+                    "201": {
+                        "description": "Voyages créés",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Fichier manquant ou invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Fichier trop volumineux",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Lignes invalides en mode atomic, rien n'a été créé",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportReport"
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/import/ics": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Crée un voyage pour chaque événement (VEVENT) du fichier .ics envoyé : réservations de vol, d'hôtel... Les journées entières, les fuseaux horaires (TZID) et les coordonnées (GEO) sont pris en compte. Les événements identiques à un voyage existant de l'utilisateur (même titre et mêmes dates) sont signalés comme doublons et ne sont pas créés. Avec dryRun=true, rien n'est créé et le rapport indique ce qui le serait.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Import"
+                ],
+                "summary": "Importer des voyages depuis un fichier iCalendar",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Fichier iCalendar (.ics, 5 Mo au maximum)",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Simulation, sans création",
+                        "name": "dryRun",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fuseau horaire IANA utilisé pour les dates des événements en UTC (UTC par défaut)",
+                        "name": "timezone",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Simulation, ou aucun voyage créé",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportReport"
+                        }
+                    },
+                    "201": {
+                        "description": "Voyages créés",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Fichier manquant ou invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Fichier trop volumineux",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/near": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne les voyages visibles par l'utilisateur situés à moins de radiusKm du point donné, du plus proche au plus lointain. Les voyages sans coordonnées sont ignorés.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trips"
+                ],
+                "summary": "Voyages à proximité",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Latitude du point",
+                        "name": "lat",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude du point",
+                        "name": "lng",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Rayon de recherche en kilomètres (50 par défaut)",
+                        "name": "radiusKm",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de l'utilisateur (administrateurs uniquement)",
+                        "name": "userId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Tous les voyages (administrateurs uniquement)",
+                        "name": "all",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Numéro de page (1 par défaut)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Nombre de voyages par page (20 par défaut, 100 au maximum)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Voyages se terminant à partir de cette date (AAAA-MM-JJ)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Voyages commençant au plus tard à cette date (AAAA-MM-JJ)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Statut du voyage (upcoming, ongoing ou past)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TripDistance"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Liens vers les pages first, prev, next et last"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Nombre total de voyages correspondants"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Paramètre invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Recherche plein texte, parmi les voyages visibles par l'utilisateur, dans le titre, la description, la localisation et les notes. Chaque mot est recherché comme préfixe, sans tenir compte des accents ni de la casse. Les résultats sont triés par pertinence (sauf paramètre sort) et accompagnés d'un extrait où les mots trouvés sont entourés de \u003cmark\u003e.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trips"
+                ],
+                "summary": "Rechercher des voyages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Mots recherchés",
+                        "name": "query",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de l'utilisateur (administrateurs uniquement)",
+                        "name": "userId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Tous les voyages (administrateurs uniquement)",
+                        "name": "all",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Numéro de page (1 par défaut)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Nombre de voyages par page (20 par défaut, 100 au maximum)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tri : startDate, title ou createdAt, préfixé de - pour un ordre décroissant (pertinence par défaut)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Voyages se terminant à partir de cette date (AAAA-MM-JJ)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Voyages commençant au plus tard à cette date (AAAA-MM-JJ)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Localisation (correspondance partielle)",
+                        "name": "location",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Statut du voyage (upcoming, ongoing ou past)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TripSearchResult"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Liens vers les pages first, prev, next et last"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Nombre total de voyages correspondants"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Le paramètre 'query' est requis",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur lors de la recherche",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/within": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne les voyages visibles par l'utilisateur situés dans le rectangle donné, triés par distance au point lat/lng ou, à défaut, au centre du rectangle. Un rectangle dont minLng est supérieur à maxLng traverse l'antiméridien.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trips"
+                ],
+                "summary": "Voyages dans une zone",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rectangle minLng,minLat,maxLng,maxLat",
+                        "name": "bbox",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude du point de référence pour le tri",
+                        "name": "lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude du point de référence pour le tri",
+                        "name": "lng",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de l'utilisateur (administrateurs uniquement)",
+                        "name": "userId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Tous les voyages (administrateurs uniquement)",
+                        "name": "all",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Numéro de page (1 par défaut)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Nombre de voyages par page (20 par défaut, 100 au maximum)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Voyages se terminant à partir de cette date (AAAA-MM-JJ)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Voyages commençant au plus tard à cette date (AAAA-MM-JJ)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Statut du voyage (upcoming, ongoing ou past)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TripDistance"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Liens vers les pages first, prev, next et last"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Nombre total de voyages correspondants"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Paramètre invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne les détails d’un voyage spécifique à partir de son ID. Avec include=itinerary, le programme jour par jour est inclus.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trips"
+                ],
+                "summary": "Récupérer un voyage par son ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Données à inclure (itinerary)",
+                        "name": "include",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TripDetail"
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Voyage non trouvé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Met à jour un voyage existant avec les nouvelles données fournies. Nécessite le rôle editor ou owner sur le voyage. Les nouvelles dates doivent inclure toutes les journées du programme.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trips"
+                ],
+                "summary": "Mettre à jour un voyage",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Nouvelles données du voyage",
+                        "name": "trip",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Trip"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Trip"
+                        }
+                    },
+                    "400": {
+                        "description": "Format invalide ou coordonnées invalides",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Voyage non trouvé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Journées du programme en dehors des nouvelles dates",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "days": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "string"
+                                    }
+                                },
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Supprime un voyage par son ID si l'utilisateur en est propriétaire (rôle owner) ou administrateur",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trips"
+                ],
+                "summary": "Supprimer un voyage",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage à supprimer",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Le voyage a bien été supprimé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Voyage introuvable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur serveur lors de la suppression",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}/balances": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Calcule, par devise, ce que chaque participant a payé et doit, ainsi qu'une liste réduite de virements permettant de solder les comptes. Une dépense sans répartition est partagée à parts égales entre les participants.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Expenses"
+                ],
+                "summary": "Équilibre des comptes",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TripBalances"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Voyage non trouvé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}/budget": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Compare, par catégorie, les dépenses du voyage au budget prévu et signale les dépassements",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Expenses"
+                ],
+                "summary": "Bilan du budget",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BudgetSummary"
+                        }
+                    },
+                    "404": {
+                        "description": "Aucun budget défini pour ce voyage",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Crée ou remplace le budget du voyage et ses plafonds par catégorie",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Expenses"
+                ],
+                "summary": "Définir le budget",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Budget",
+                        "name": "budget",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Budget"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Budget"
+                        }
+                    },
+                    "400": {
+                        "description": "Format invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Voyage non trouvé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}/calendar.ics": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne un fichier .ics (RFC 5545) contenant le voyage sur des journées entières, ainsi que les étapes et journées de son programme.",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "Calendar"
+                ],
+                "summary": "Exporter un voyage au format iCalendar",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Calendrier iCalendar",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Voyage non trouvé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Le voyage n'a pas de date de début valide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}/days": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne les journées d'un voyage et leurs étapes, triées par date puis par position",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Itinerary"
+                ],
+                "summary": "Programme d'un voyage",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ItineraryDay"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Voyage non trouvé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ajoute une journée au programme du voyage, éventuellement avec ses étapes. La date doit être comprise entre les dates du voyage.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Itinerary"
+                ],
+                "summary": "Ajouter une journée",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Journée",
+                        "name": "day",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ItineraryDay"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ItineraryDay"
+                        }
+                    },
+                    "400": {
+                        "description": "Format invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Voyage non trouvé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}/days/{day}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne une journée du programme avec ses étapes",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Itinerary"
+                ],
+                "summary": "Récupérer une journée",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de la journée",
+                        "name": "day",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ItineraryDay"
+                        }
+                    },
+                    "404": {
+                        "description": "Journée non trouvée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Met à jour la date, la position, le titre ou les notes d'une journée. Les étapes se gèrent via leurs propres routes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Itinerary"
+                ],
+                "summary": "Mettre à jour une journée",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de la journée",
+                        "name": "day",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Nouvelles données de la journée",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ItineraryDay"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ItineraryDay"
+                        }
+                    },
+                    "400": {
+                        "description": "Format invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Journée non trouvée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Supprime une journée du programme ainsi que toutes ses étapes",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Itinerary"
+                ],
+                "summary": "Supprimer une journée",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de la journée",
+                        "name": "day",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "La journée a bien été supprimée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Journée non trouvée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}/days/{day}/stops": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne les étapes d'une journée, triées par position puis par heure de début",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Itinerary"
+                ],
+                "summary": "Étapes d'une journée",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de la journée",
+                        "name": "day",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Stop"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Journée non trouvée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ajoute une étape à une journée du programme",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Itinerary"
+                ],
+                "summary": "Ajouter une étape",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de la journée",
+                        "name": "day",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Étape",
+                        "name": "stop",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Stop"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Stop"
+                        }
+                    },
+                    "400": {
+                        "description": "Format invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Journée non trouvée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}/days/{day}/stops/{stop}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Met à jour une étape. Le champ dayId permet de déplacer l'étape vers une autre journée du même voyage.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Itinerary"
+                ],
+                "summary": "Mettre à jour une étape",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de la journée",
+                        "name": "day",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de l'étape",
+                        "name": "stop",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Nouvelles données de l'étape",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Stop"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Stop"
+                        }
+                    },
+                    "400": {
+                        "description": "Format invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Étape non trouvée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Supprime une étape d'une journée",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Itinerary"
+                ],
+                "summary": "Supprimer une étape",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de la journée",
+                        "name": "day",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de l'étape",
+                        "name": "stop",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "L'étape a bien été supprimée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Étape non trouvée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}/expenses": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne les dépenses d'un voyage, éventuellement filtrées par catégorie",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Expenses"
+                ],
+                "summary": "Dépenses d'un voyage",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Catégorie",
+                        "name": "category",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Expense"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Voyage non trouvé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ajoute une dépense au voyage. Si payerId est absent, l'utilisateur connecté est considéré comme le payeur.\nLa dépense est répartie entre les participants selon splitType : equal (parts égales, par défaut entre tous les participants), shares (au prorata des parts) ou exact (montants fixés).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Expenses"
+                ],
+                "summary": "Ajouter une dépense",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dépense",
+                        "name": "expense",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Expense"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Expense"
+                        }
+                    },
+                    "400": {
+                        "description": "Format invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Voyage non trouvé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}/expenses/{expense}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne une dépense du voyage",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Expenses"
+                ],
+                "summary": "Récupérer une dépense",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de la dépense",
+                        "name": "expense",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Expense"
+                        }
+                    },
+                    "404": {
+                        "description": "Dépense non trouvée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Met à jour une dépense du voyage. Si splits est absent, la répartition existante est recalculée sur le nouveau montant.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Expenses"
+                ],
+                "summary": "Mettre à jour une dépense",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de la dépense",
+                        "name": "expense",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Nouvelles données de la dépense",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Expense"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Expense"
+                        }
+                    },
+                    "400": {
+                        "description": "Format invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Dépense non trouvée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Supprime une dépense du voyage",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Expenses"
+                ],
+                "summary": "Supprimer une dépense",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de la dépense",
+                        "name": "expense",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "La dépense a bien été supprimée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Dépense non trouvée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}/export.gpx": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne un fichier GPX 1.1 avec le voyage et ses étapes localisés, et une route reliant les étapes dans l'ordre du programme.",
+                "produces": [
+                    "application/gpx+xml"
+                ],
+                "tags": [
+                    "Export"
+                ],
+                "summary": "Exporter un voyage au format GPX",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Fichier GPX",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Voyage non trouvé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}/export.kml": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne un fichier KML 2.2 (Google Earth) avec le voyage et ses étapes localisés, et une ligne reliant les étapes dans l'ordre du programme.",
+                "produces": [
+                    "application/vnd.google-earth.kml+xml"
+                ],
+                "tags": [
+                    "Export"
+                ],
+                "summary": "Exporter un voyage au format KML",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Fichier KML",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Voyage non trouvé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}/invitations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne les invitations envoyées pour un voyage. Réservé aux propriétaires du voyage.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invitations"
+                ],
+                "summary": "Invitations d'un voyage",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Invitation"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Crée une invitation signée et limitée dans le temps pour une adresse email, et l'envoie par email. Réservé aux propriétaires du voyage.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invitations"
+                ],
+                "summary": "Inviter une personne sur un voyage",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Email invité et rôle proposé (viewer par défaut)",
+                        "name": "invitation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "email": {
+                                    "type": "string"
+                                },
+                                "role": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Invitation"
+                        }
+                    },
+                    "400": {
+                        "description": "Format invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Cette personne est déjà membre du voyage",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}/invitations/{invitation}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Annule une invitation encore en attente. Réservé aux propriétaires du voyage.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invitations"
+                ],
+                "summary": "Annuler une invitation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de l'invitation",
+                        "name": "invitation",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "L'invitation a bien été annulée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Invitation non trouvée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Cette invitation a déjà été traitée",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}/itinerary.pdf": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne un document PDF A4 reprenant le voyage (titre, dates, lieu, description, notes), un emplacement de carte indiquant sa position, son programme jour par jour et ses dépenses avec leur total par devise. La langue est choisie par le paramètre lang, sinon d'après l'en-tête Accept-Language (français par défaut).",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Export"
+                ],
+                "summary": "Carnet de voyage imprimable (PDF)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Langue du document (fr ou en)",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Document PDF",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Langue non prise en charge",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Voyage non trouvé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}/members": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne les utilisateurs ayant accès au voyage et leur rôle (owner, editor ou viewer)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Membres d'un voyage",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TripMember"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Voyage non trouvé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Donne accès au voyage à un utilisateur, désigné par son ID ou son email. Réservé aux propriétaires du voyage.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Ajouter un membre",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Utilisateur et rôle (viewer par défaut)",
+                        "name": "member",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "email": {
+                                    "type": "string"
+                                },
+                                "role": {
+                                    "type": "string"
+                                },
+                                "userId": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.TripMember"
+                        }
+                    },
+                    "400": {
+                        "description": "Format invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Utilisateur non trouvé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "L'utilisateur est déjà membre du voyage",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}/members/{user}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Modifie le rôle d'un membre du voyage. Réservé aux propriétaires ; le créateur du voyage reste propriétaire.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Changer le rôle d'un membre",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de l'utilisateur",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Nouveau rôle",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "role": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TripMember"
+                        }
+                    },
+                    "400": {
+                        "description": "Rôle invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Membre non trouvé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retire l'accès d'un utilisateur au voyage. Réservé aux propriétaires, sauf pour quitter soi-même le voyage.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Retirer un membre",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de l'utilisateur",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Le membre a bien été retiré",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Membre non trouvé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}/settlements": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne les remboursements enregistrés entre participants",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Expenses"
+                ],
+                "summary": "Remboursements d'un voyage",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Settlement"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Voyage non trouvé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Enregistre un paiement d'un participant à un autre, qui vient réduire leurs soldes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Expenses"
+                ],
+                "summary": "Enregistrer un remboursement",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Remboursement",
+                        "name": "settlement",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Settlement"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Settlement"
+                        }
+                    },
+                    "400": {
+                        "description": "Format invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Voyage non trouvé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}/settlements/{settlement}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Supprime un remboursement enregistré par erreur",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Expenses"
+                ],
+                "summary": "Annuler un remboursement",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID du remboursement",
+                        "name": "settlement",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Le remboursement a bien été supprimé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Remboursement non trouvé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}/share-links": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne les liens de partage du voyage (sans leur jeton). Réservé aux propriétaires du voyage.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sharing"
+                ],
+                "summary": "Liens de partage d'un voyage",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ShareLink"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Crée un lien public en lecture seule vers le voyage, révocable et éventuellement limité dans le temps. Le jeton n'est retourné qu'une seule fois. Réservé aux propriétaires du voyage.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sharing"
+                ],
+                "summary": "Créer un lien de partage",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Options du lien",
+                        "name": "options",
+                        "in": "body",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "expiresAt": {
+                                    "type": "string"
+                                },
+                                "includeNotes": {
+                                    "type": "boolean"
+                                },
+                                "includeOwner": {
+                                    "type": "boolean"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ShareLink"
+                        }
+                    },
+                    "400": {
+                        "description": "Format invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}/share-links/{link}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Désactive définitivement un lien de partage. Réservé aux propriétaires du voyage.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sharing"
+                ],
+                "summary": "Révoquer un lien de partage",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID du voyage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID du lien",
+                        "name": "link",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Le lien a bien été révoqué",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Lien non trouvé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne tous les utilisateurs enregistrés",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Liste un utilisateur en fonction de son email",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Email de l'utilisateur",
+                        "name": "email",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.User"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne tous les utilisateurs enregistrés",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Liste tous les utilisateurs",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.User"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Met à jour un utilisateur",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Mise à jour d'un utilisateur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identifiant de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.User"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/trips": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne les voyages dont un utilisateur est propriétaire ou membre. Un utilisateur ne peut consulter que les siens, sauf s'il est administrateur.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trips"
+                ],
+                "summary": "Récupérer les voyages d’un utilisateur",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Numéro de page (1 par défaut)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Nombre de voyages par page (20 par défaut, 100 au maximum)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tri : startDate, title ou createdAt, préfixé de - pour un ordre décroissant",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Voyages se terminant à partir de cette date (AAAA-MM-JJ)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Voyages commençant au plus tard à cette date (AAAA-MM-JJ)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Localisation (correspondance partielle)",
+                        "name": "location",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Statut du voyage (upcoming, ongoing ou past)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Trip"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Liens vers les pages first, prev, next et last"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Nombre total de voyages correspondants"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Paramètre invalide",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Accès refusé",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur lors de la récupération des voyages",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "geo.Feature": {
+            "type": "object",
+            "properties": {
+                "bbox": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "geometry": {
+                    "$ref": "#/definitions/geo.PointGeometry"
+                },
+                "id": {},
+                "properties": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "type": {
+                    "type": "string",
+                    "example": "Feature"
+                }
+            }
+        },
+        "geo.FeatureCollection": {
+            "type": "object",
+            "properties": {
+                "bbox": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "features": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/geo.Feature"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "example": "FeatureCollection"
+                }
+            }
+        },
+        "geo.PointGeometry": {
+            "type": "object",
+            "properties": {
+                "coordinates": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "example": "Point"
+                }
+            }
+        },
+        "models.APIToken": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lastUsedAt": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Synchronisation agenda"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "tm_pat_Zx81"
+                },
+                "revokedAt": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "trips:read"
+                    ]
+                },
+                "token": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AuditEvent": {
+            "type": "object",
+            "properties": {
+                "actorId": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "alice@example.com"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "login.locked"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Budget": {
+            "type": "object",
+            "required": [
+                "currency"
+            ],
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BudgetCategory"
+                    }
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "tripId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BudgetCategory": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "example": "restaurant"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "planned": {
+                    "type": "number",
+                    "example": 300
+                }
+            }
+        },
+        "models.BudgetCategorySummary": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "overrun": {
+                    "type": "boolean"
+                },
+                "planned": {
+                    "type": "number"
+                },
+                "remaining": {
+                    "type": "number"
+                },
+                "spent": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.BudgetSummary": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BudgetCategorySummary"
+                    }
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "otherCurrencies": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "overrun": {
+                    "type": "boolean"
+                },
+                "planned": {
+                    "type": "number"
+                },
+                "remaining": {
+                    "type": "number"
+                },
+                "spent": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.BulkTripResult": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "updated"
+                }
+            }
+        },
+        "models.CalendarFeed": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Expense": {
+            "type": "object",
+            "required": [
+                "amount",
+                "category",
+                "currency"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 42.5
+                },
+                "category": {
+                    "type": "string",
+                    "example": "restaurant"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2025-01-02"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "payerId": {
+                    "type": "integer"
+                },
+                "splitType": {
+                    "type": "string",
+                    "example": "equal"
+                },
+                "splits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ExpenseSplit"
+                    }
+                },
+                "tripId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ExpenseSplit": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "shares": {
+                    "type": "number"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ImportReport": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "dryRun": {
+                    "type": "boolean"
+                },
+                "duplicates": {
+                    "type": "integer"
+                },
+                "invalid": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ImportRow"
+                    }
+                }
+            }
+        },
+        "models.ImportRow": {
+            "type": "object",
+            "properties": {
+                "duplicateOf": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "row": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "created"
+                },
+                "trip": {
+                    "$ref": "#/definitions/models.Trip"
+                }
+            }
+        },
+        "models.Invitation": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invitedById": {
+                    "type": "integer"
+                },
+                "respondedAt": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "viewer"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "pending"
+                },
+                "trip": {
+                    "$ref": "#/definitions/models.Trip"
+                },
+                "tripId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ItineraryDay": {
+            "type": "object",
+            "required": [
+                "date"
+            ],
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2025-01-02"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "stops": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Stop"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "tripId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RecoveryCodes": {
+            "type": "object",
+            "properties": {
+                "recoveryCodes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "k3j9d-x8q2m",
+                        "p7w4n-c5v1b"
+                    ]
+                }
+            }
+        },
+        "models.Register": {
+            "type": "object",
+            "required": [
+                "email",
+                "name",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "jean@example.com"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Jean Dupont"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6,
+                    "example": "secret123"
+                }
+            }
+        },
+        "models.RegisterResponse": {
+            "type": "object",
+            "properties": {
+                "expiresIn": {
+                    "type": "integer",
+                    "example": 900
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Inscription réussie"
+                },
+                "refreshToken": {
+                    "type": "string",
+                    "example": "q3Jx0v8bR2m..."
+                },
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                }
+            }
+        },
+        "models.Session": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "current": {
+                    "type": "boolean"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "lastSeenAt": {
+                    "type": "string"
+                },
+                "revokedAt": {
+                    "type": "string"
+                },
+                "userAgent": {
+                    "type": "string",
+                    "example": "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X)"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Settlement": {
+            "type": "object",
+            "required": [
+                "amount",
+                "currency",
+                "fromUserId",
+                "toUserId"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 25
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2025-01-05"
+                },
+                "fromUserId": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "toUserId": {
+                    "type": "integer"
+                },
+                "tripId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ShareLink": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdById": {
+                    "type": "integer"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "includeNotes": {
+                    "type": "boolean"
+                },
+                "includeOwner": {
+                    "type": "boolean"
+                },
+                "revokedAt": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "tripId": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SharedDay": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "stops": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SharedStop"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SharedStop": {
+            "type": "object",
+            "properties": {
+                "endTime": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "startTime": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SharedTrip": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SharedDay"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "endDate": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "ownerName": {
+                    "type": "string"
+                },
+                "startDate": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Stop": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
             "properties": {
-                "email": {
+                "dayId": {
+                    "type": "integer"
+                },
+                "endTime": {
                     "type": "string",
-                    "example": "jean@example.com"
+                    "example": "11:00"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
                 },
                 "name": {
-                    "type": "string",
-                    "example": "Jean Dupont"
+                    "type": "string"
                 },
-                "password": {
+                "notes": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "startTime": {
                     "type": "string",
-                    "minLength": 6,
-                    "example": "secret123"
+                    "example": "09:30"
                 }
             }
         },
-        "models.RegisterResponse": {
+        "models.TokenPair": {
             "type": "object",
             "properties": {
-                "message": {
+                "expiresIn": {
+                    "type": "integer",
+                    "example": 900
+                },
+                "refreshToken": {
                     "type": "string",
-                    "example": "Inscription réussie"
+                    "example": "q3Jx0v8bR2m..."
                 },
                 "token": {
                     "type": "string",
@@ -644,6 +6272,20 @@ const docTemplate = `{
                 }
             }
         },
+        "models.Transfer": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "fromUserId": {
+                    "type": "integer"
+                },
+                "toUserId": {
+                    "type": "integer"
+                }
+            }
+        },
         "models.Trip": {
             "type": "object",
             "required": [
@@ -682,6 +6324,215 @@ const docTemplate = `{
                 }
             }
         },
+        "models.TripBalances": {
+            "type": "object",
+            "properties": {
+                "balances": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.UserBalance"
+                    }
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "transfers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Transfer"
+                    }
+                }
+            }
+        },
+        "models.TripDetail": {
+            "type": "object",
+            "required": [
+                "title"
+            ],
+            "properties": {
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ItineraryDay"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "endDate": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "startDate": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TripDistance": {
+            "type": "object",
+            "required": [
+                "title"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "distanceKm": {
+                    "type": "number",
+                    "example": 12.4
+                },
+                "endDate": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "startDate": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TripMember": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "editor"
+                },
+                "tripId": {
+                    "type": "integer"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TripSearchResult": {
+            "type": "object",
+            "required": [
+                "title"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "endDate": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "snippet": {
+                    "type": "string",
+                    "example": "Week-end à \u003cmark\u003eParis\u003c/mark\u003e en famille"
+                },
+                "startDate": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TwoFactorChallenge": {
+            "type": "object",
+            "properties": {
+                "challengeToken": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
+                "expiresIn": {
+                    "type": "integer",
+                    "example": 300
+                },
+                "twoFactorRequired": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.TwoFactorSetup": {
+            "type": "object",
+            "properties": {
+                "otpauthUri": {
+                    "type": "string",
+                    "example": "otpauth://totp/TravelMate:jean@example.com?secret=JBSWY3DPEHPK3PXP\u0026issuer=TravelMate"
+                },
+                "qrCode": {
+                    "type": "string",
+                    "example": "data:image/png;base64,iVBORw0KGgo..."
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+                }
+            }
+        },
         "models.User": {
             "type": "object",
             "required": [
@@ -692,6 +6543,9 @@ const docTemplate = `{
                 "email": {
                     "type": "string"
                 },
+                "emailVerified": {
+                    "type": "boolean"
+                },
                 "id": {
                     "type": "integer"
                 },
@@ -700,6 +6554,30 @@ const docTemplate = `{
                 },
                 "name": {
                     "type": "string"
+                },
+                "twoFactorEnabled": {
+                    "description": "Double authentification : le secret est enregistré dès l'activation et n'est utilisé\nqu'une fois confirmé (TwoFactorEnabled). TOTPLastStep empêche de rejouer un code.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.UserBalance": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "net": {
+                    "type": "number"
+                },
+                "owed": {
+                    "type": "number"
+                },
+                "paid": {
+                    "type": "number"
+                },
+                "userId": {
+                    "type": "integer"
                 }
             }
         }
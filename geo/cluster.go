@@ -0,0 +1,84 @@
+package geo
+
+import "math"
+
+const (
+	// Taille en pixels d'une tuile de carte
+	tileSize = 256
+	// Latitude maximale de la projection Web Mercator
+	maxMercatorLat = 85.05112878
+)
+
+// Cluster est un groupe de points proches à un niveau de zoom donné.
+// Indexes désigne les points du groupe dans la liste d'origine.
+type Cluster struct {
+	Latitude  float64
+	Longitude float64
+	BBox      BBox
+	Indexes   []int
+}
+
+// ClusterPoints regroupe les points situés à moins de cellPx pixels du premier point d'un
+// groupe au niveau de zoom donné (projection Web Mercator, comme les cartes Leaflet).
+// Une grille de cellPx pixels limite la recherche aux groupes des cases voisines.
+// Le centre d'un groupe est la moyenne de ses points. Les groupes sont retournés dans
+// l'ordre de leur premier point.
+func ClusterPoints(lats, lngs []float64, zoom int, cellPx float64) []Cluster {
+	worldPx := tileSize * math.Exp2(float64(zoom))
+
+	type cell struct{ x, y int64 }
+	type seed struct {
+		x, y    float64
+		cluster *Cluster
+	}
+	byCell := map[cell][]seed{}
+	var clusters []*Cluster
+
+	for i := range lats {
+		x, y := project(lats[i], lngs[i], worldPx)
+		key := cell{int64(math.Floor(x / cellPx)), int64(math.Floor(y / cellPx))}
+
+		var cluster *Cluster
+		for dx := int64(-1); dx <= 1 && cluster == nil; dx++ {
+			for dy := int64(-1); dy <= 1 && cluster == nil; dy++ {
+				for _, s := range byCell[cell{key.x + dx, key.y + dy}] {
+					if math.Hypot(s.x-x, s.y-y) <= cellPx {
+						cluster = s.cluster
+						break
+					}
+				}
+			}
+		}
+		if cluster == nil {
+			cluster = &Cluster{BBox: BBox{MinLng: lngs[i], MinLat: lats[i], MaxLng: lngs[i], MaxLat: lats[i]}}
+			byCell[key] = append(byCell[key], seed{x, y, cluster})
+			clusters = append(clusters, cluster)
+		}
+
+		cluster.Indexes = append(cluster.Indexes, i)
+		cluster.Latitude += lats[i]
+		cluster.Longitude += lngs[i]
+		cluster.BBox.MinLat = math.Min(cluster.BBox.MinLat, lats[i])
+		cluster.BBox.MaxLat = math.Max(cluster.BBox.MaxLat, lats[i])
+		cluster.BBox.MinLng = math.Min(cluster.BBox.MinLng, lngs[i])
+		cluster.BBox.MaxLng = math.Max(cluster.BBox.MaxLng, lngs[i])
+	}
+
+	result := make([]Cluster, 0, len(clusters))
+	for _, cluster := range clusters {
+		count := float64(len(cluster.Indexes))
+		cluster.Latitude /= count
+		cluster.Longitude /= count
+		result = append(result, *cluster)
+	}
+	return result
+}
+
+// project convertit des coordonnées en pixels Web Mercator pour une carte de worldPx pixels de côté
+func project(lat, lng, worldPx float64) (float64, float64) {
+	lat = math.Max(-maxMercatorLat, math.Min(maxMercatorLat, lat))
+	x := (lng + 180) / 360 * worldPx
+	sin := math.Sin(toRadians(lat))
+	y := (0.5 - math.Log((1+sin)/(1-sin))/(4*math.Pi)) * worldPx
+	return x, y
+}
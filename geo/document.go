@@ -0,0 +1,26 @@
+package geo
+
+// Point est un lieu nommé (voyage ou étape) exporté en GPX ou en KML.
+// Begin et End sont des dates AAAA-MM-JJ, facultatives.
+type Point struct {
+	Name        string
+	Description string
+	Kind        string
+	Latitude    float64
+	Longitude   float64
+	Begin       string
+	End         string
+}
+
+// Route est un itinéraire reliant des points dans l'ordre
+type Route struct {
+	Name   string
+	Points []Point
+}
+
+// Document regroupe les points et itinéraires d'un export
+type Document struct {
+	Name      string
+	Waypoints []Point
+	Routes    []Route
+}
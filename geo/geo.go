@@ -0,0 +1,127 @@
+package geo
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// EarthRadiusKm est le rayon moyen de la Terre
+const EarthRadiusKm = 6371.0
+
+// ValidateCoordinates vérifie que la latitude et la longitude sont dans les bornes du globe
+func ValidateCoordinates(lat, lng float64) error {
+	if math.IsNaN(lat) || lat < -90 || lat > 90 {
+		return errors.New("La latitude doit être comprise entre -90 et 90")
+	}
+	if math.IsNaN(lng) || lng < -180 || lng > 180 {
+		return errors.New("La longitude doit être comprise entre -180 et 180")
+	}
+	return nil
+}
+
+// DistanceKm retourne la distance à vol d'oiseau entre deux points (formule de haversine)
+func DistanceKm(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := toRadians(lat2 - lat1)
+	dLng := toRadians(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+// BBox est un rectangle de coordonnées. Si MinLng > MaxLng, le rectangle traverse
+// l'antiméridien (longitude 180).
+type BBox struct {
+	MinLng float64
+	MinLat float64
+	MaxLng float64
+	MaxLat float64
+}
+
+// ParseBBox lit un rectangle au format minLng,minLat,maxLng,maxLat (ordre GeoJSON)
+func ParseBBox(value string) (BBox, error) {
+	invalid := errors.New("Le paramètre 'bbox' doit être au format minLng,minLat,maxLng,maxLat")
+
+	parts := strings.Split(value, ",")
+	if len(parts) != 4 {
+		return BBox{}, invalid
+	}
+	var numbers [4]float64
+	for i, part := range parts {
+		number, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return BBox{}, invalid
+		}
+		numbers[i] = number
+	}
+
+	box := BBox{MinLng: numbers[0], MinLat: numbers[1], MaxLng: numbers[2], MaxLat: numbers[3]}
+	if err := ValidateCoordinates(box.MinLat, box.MinLng); err != nil {
+		return BBox{}, err
+	}
+	if err := ValidateCoordinates(box.MaxLat, box.MaxLng); err != nil {
+		return BBox{}, err
+	}
+	if box.MinLat > box.MaxLat {
+		return BBox{}, errors.New("La latitude minimale doit être inférieure à la latitude maximale")
+	}
+	return box, nil
+}
+
+// Around retourne le plus petit rectangle contenant le cercle de rayon radiusKm autour du point.
+// Il sert de pré-filtre : les points du rectangle ne sont pas tous dans le cercle.
+func Around(lat, lng, radiusKm float64) BBox {
+	dLat := radiusKm / EarthRadiusKm * 180 / math.Pi
+	box := BBox{MinLat: lat - dLat, MaxLat: lat + dLat, MinLng: -180, MaxLng: 180}
+
+	// Près des pôles, le cercle couvre toutes les longitudes
+	if box.MinLat <= -90 || box.MaxLat >= 90 {
+		box.MinLat = math.Max(box.MinLat, -90)
+		box.MaxLat = math.Min(box.MaxLat, 90)
+		return box
+	}
+
+	dLng := math.Asin(math.Sin(radiusKm/EarthRadiusKm)/math.Cos(toRadians(lat))) * 180 / math.Pi
+	if math.IsNaN(dLng) || dLng >= 180 {
+		return box
+	}
+	box.MinLng = normalizeLng(lng - dLng)
+	box.MaxLng = normalizeLng(lng + dLng)
+	return box
+}
+
+// Center retourne le centre du rectangle
+func (b BBox) Center() (lat, lng float64) {
+	lat = (b.MinLat + b.MaxLat) / 2
+	if b.MinLng <= b.MaxLng {
+		return lat, (b.MinLng + b.MaxLng) / 2
+	}
+	return lat, normalizeLng((b.MinLng + b.MaxLng + 360) / 2)
+}
+
+// Contains indique si le point est dans le rectangle
+func (b BBox) Contains(lat, lng float64) bool {
+	if lat < b.MinLat || lat > b.MaxLat {
+		return false
+	}
+	if b.MinLng <= b.MaxLng {
+		return lng >= b.MinLng && lng <= b.MaxLng
+	}
+	return lng >= b.MinLng || lng <= b.MaxLng
+}
+
+// normalizeLng ramène une longitude dans l'intervalle [-180, 180]
+func normalizeLng(lng float64) float64 {
+	for lng > 180 {
+		lng -= 360
+	}
+	for lng < -180 {
+		lng += 360
+	}
+	return lng
+}
@@ -0,0 +1,43 @@
+package geo
+
+// FeatureCollection est une collection GeoJSON (RFC 7946)
+type FeatureCollection struct {
+	Type     string    `json:"type" example:"FeatureCollection"`
+	BBox     []float64 `json:"bbox,omitempty"`
+	Features []Feature `json:"features"`
+}
+
+// Feature est un objet GeoJSON localisé par un point
+type Feature struct {
+	Type       string                 `json:"type" example:"Feature"`
+	ID         interface{}            `json:"id,omitempty"`
+	BBox       []float64              `json:"bbox,omitempty"`
+	Geometry   PointGeometry          `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// PointGeometry est une géométrie GeoJSON de type Point ; les coordonnées sont dans
+// l'ordre longitude, latitude
+type PointGeometry struct {
+	Type        string    `json:"type" example:"Point"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+// NewFeatureCollection retourne une collection vide
+func NewFeatureCollection() *FeatureCollection {
+	return &FeatureCollection{Type: "FeatureCollection", Features: []Feature{}}
+}
+
+// NewPointFeature retourne un objet GeoJSON situé au point donné
+func NewPointFeature(lat, lng float64, properties map[string]interface{}) Feature {
+	return Feature{
+		Type:       "Feature",
+		Geometry:   PointGeometry{Type: "Point", Coordinates: []float64{lng, lat}},
+		Properties: properties,
+	}
+}
+
+// GeoJSON retourne le rectangle au format bbox de GeoJSON : minLng, minLat, maxLng, maxLat
+func (b BBox) GeoJSON() []float64 {
+	return []float64{b.MinLng, b.MinLat, b.MaxLng, b.MaxLat}
+}
@@ -0,0 +1,72 @@
+package geo
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+type gpxFile struct {
+	XMLName   xml.Name      `xml:"gpx"`
+	Version   string        `xml:"version,attr"`
+	Creator   string        `xml:"creator,attr"`
+	Namespace string        `xml:"xmlns,attr"`
+	Metadata  gpxMetadata   `xml:"metadata"`
+	Waypoints []gpxWaypoint `xml:"wpt"`
+	Routes    []gpxRoute    `xml:"rte"`
+}
+
+type gpxMetadata struct {
+	Name string `xml:"name,omitempty"`
+	Time string `xml:"time"`
+}
+
+type gpxWaypoint struct {
+	Lat         float64 `xml:"lat,attr"`
+	Lon         float64 `xml:"lon,attr"`
+	Name        string  `xml:"name,omitempty"`
+	Description string  `xml:"desc,omitempty"`
+	Type        string  `xml:"type,omitempty"`
+}
+
+type gpxRoute struct {
+	Name   string        `xml:"name,omitempty"`
+	Points []gpxWaypoint `xml:"rtept"`
+}
+
+func toGPXWaypoint(point Point) gpxWaypoint {
+	return gpxWaypoint{
+		Lat:         point.Latitude,
+		Lon:         point.Longitude,
+		Name:        point.Name,
+		Description: point.Description,
+		Type:        point.Kind,
+	}
+}
+
+// WriteGPX écrit le document au format GPX 1.1 : un waypoint par point et un itinéraire (rte) par route
+func WriteGPX(w io.Writer, doc *Document) error {
+	file := gpxFile{
+		Version:   "1.1",
+		Creator:   "TravelMate",
+		Namespace: "http://www.topografix.com/GPX/1/1",
+		Metadata:  gpxMetadata{Name: doc.Name, Time: time.Now().UTC().Format(time.RFC3339)},
+	}
+	for _, point := range doc.Waypoints {
+		file.Waypoints = append(file.Waypoints, toGPXWaypoint(point))
+	}
+	for _, route := range doc.Routes {
+		rte := gpxRoute{Name: route.Name}
+		for _, point := range route.Points {
+			rte.Points = append(rte.Points, toGPXWaypoint(point))
+		}
+		file.Routes = append(file.Routes, rte)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(file)
+}
@@ -0,0 +1,92 @@
+package geo
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type kmlFile struct {
+	XMLName   xml.Name    `xml:"kml"`
+	Namespace string      `xml:"xmlns,attr"`
+	Document  kmlDocument `xml:"Document"`
+}
+
+type kmlDocument struct {
+	Name    string      `xml:"name,omitempty"`
+	Folders []kmlFolder `xml:"Folder"`
+}
+
+type kmlFolder struct {
+	Name       string         `xml:"name"`
+	Placemarks []kmlPlacemark `xml:"Placemark"`
+}
+
+type kmlPlacemark struct {
+	Name        string         `xml:"name,omitempty"`
+	Description string         `xml:"description,omitempty"`
+	TimeSpan    *kmlTimeSpan   `xml:"TimeSpan,omitempty"`
+	Point       *kmlPoint      `xml:"Point,omitempty"`
+	LineString  *kmlLineString `xml:"LineString,omitempty"`
+}
+
+type kmlTimeSpan struct {
+	Begin string `xml:"begin,omitempty"`
+	End   string `xml:"end,omitempty"`
+}
+
+type kmlPoint struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+type kmlLineString struct {
+	Tessellate  int    `xml:"tessellate"`
+	Coordinates string `xml:"coordinates"`
+}
+
+// kmlCoordinates formate un point dans l'ordre longitude,latitude de KML
+func kmlCoordinates(point Point) string {
+	return strconv.FormatFloat(point.Longitude, 'f', -1, 64) + "," + strconv.FormatFloat(point.Latitude, 'f', -1, 64)
+}
+
+// WriteKML écrit le document au format KML 2.2 : un dossier de repères pour les points,
+// daté si les points ont des dates, et un dossier de lignes pour les routes
+func WriteKML(w io.Writer, doc *Document) error {
+	points := kmlFolder{Name: "Lieux"}
+	for _, point := range doc.Waypoints {
+		placemark := kmlPlacemark{
+			Name:        point.Name,
+			Description: point.Description,
+			Point:       &kmlPoint{Coordinates: kmlCoordinates(point)},
+		}
+		if point.Begin != "" || point.End != "" {
+			placemark.TimeSpan = &kmlTimeSpan{Begin: point.Begin, End: point.End}
+		}
+		points.Placemarks = append(points.Placemarks, placemark)
+	}
+
+	routes := kmlFolder{Name: "Itinéraires"}
+	for _, route := range doc.Routes {
+		coordinates := make([]string, 0, len(route.Points))
+		for _, point := range route.Points {
+			coordinates = append(coordinates, kmlCoordinates(point))
+		}
+		routes.Placemarks = append(routes.Placemarks, kmlPlacemark{
+			Name:       route.Name,
+			LineString: &kmlLineString{Tessellate: 1, Coordinates: strings.Join(coordinates, " ")},
+		})
+	}
+
+	file := kmlFile{
+		Namespace: "http://www.opengis.net/kml/2.2",
+		Document:  kmlDocument{Name: doc.Name, Folders: []kmlFolder{points, routes}},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(file)
+}
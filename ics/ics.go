@@ -0,0 +1,139 @@
+package ics
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	dateFormat     = "20060102"
+	dateTimeFormat = "20060102T150405"
+	// Longueur maximale d'une ligne en octets, hors CRLF (RFC 5545, section 3.1)
+	maxLineLength = 75
+)
+
+// Calendar est un calendrier iCalendar (RFC 5545)
+type Calendar struct {
+	ProdID string
+	Name   string
+	Events []Event
+}
+
+// Event est un événement (VEVENT). Un événement AllDay ne tient compte que de la date de
+// Start et de End, End étant le dernier jour inclus. Sinon, les heures sont écrites sans
+// fuseau horaire (heure locale du lieu) sauf si Start est en UTC.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	HasGeo      bool
+	Latitude    float64
+	Longitude   float64
+}
+
+// Encode écrit le calendrier au format iCalendar
+func (cal *Calendar) Encode(w io.Writer) error {
+	enc := &encoder{w: w}
+	enc.line("BEGIN", "VCALENDAR")
+	enc.line("VERSION", "2.0")
+	enc.line("PRODID", cal.ProdID)
+	enc.line("CALSCALE", "GREGORIAN")
+	enc.line("METHOD", "PUBLISH")
+	if cal.Name != "" {
+		enc.line("X-WR-CALNAME", escapeText(cal.Name))
+	}
+
+	stamp := time.Now().UTC().Format(dateTimeFormat) + "Z"
+	for _, event := range cal.Events {
+		enc.line("BEGIN", "VEVENT")
+		enc.line("UID", event.UID)
+		enc.line("DTSTAMP", stamp)
+		if event.AllDay {
+			// DTEND est exclusif pour les journées entières
+			enc.line("DTSTART;VALUE=DATE", event.Start.Format(dateFormat))
+			enc.line("DTEND;VALUE=DATE", event.End.AddDate(0, 0, 1).Format(dateFormat))
+		} else {
+			enc.line("DTSTART", formatDateTime(event.Start))
+			enc.line("DTEND", formatDateTime(event.End))
+		}
+		enc.line("SUMMARY", escapeText(event.Summary))
+		if event.Description != "" {
+			enc.line("DESCRIPTION", escapeText(event.Description))
+		}
+		if event.Location != "" {
+			enc.line("LOCATION", escapeText(event.Location))
+		}
+		if event.HasGeo {
+			enc.line("GEO", strconv.FormatFloat(event.Latitude, 'f', 6, 64)+";"+strconv.FormatFloat(event.Longitude, 'f', 6, 64))
+		}
+		if event.URL != "" {
+			enc.line("URL", event.URL)
+		}
+		enc.line("END", "VEVENT")
+	}
+
+	enc.line("END", "VCALENDAR")
+	return enc.err
+}
+
+// String retourne le calendrier au format iCalendar
+func (cal *Calendar) String() string {
+	var b strings.Builder
+	cal.Encode(&b)
+	return b.String()
+}
+
+func formatDateTime(t time.Time) string {
+	if t.Location() == time.UTC {
+		return t.Format(dateTimeFormat) + "Z"
+	}
+	return t.Format(dateTimeFormat)
+}
+
+// escapeText échappe une valeur de type TEXT (RFC 5545, section 3.3.11)
+func escapeText(value string) string {
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(value)
+}
+
+// encoder écrit les lignes du calendrier en les repliant à 75 octets
+type encoder struct {
+	w   io.Writer
+	err error
+}
+
+func (e *encoder) line(name, value string) {
+	if e.err != nil {
+		return
+	}
+	content := name + ":" + value
+	var b strings.Builder
+	width := 0
+	for _, r := range content {
+		size := utf8.RuneLen(r)
+		// Les lignes repliées commencent par une espace, qui compte dans la longueur
+		if width+size > maxLineLength {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+	_, e.err = fmt.Fprint(e.w, b.String())
+}
@@ -0,0 +1,312 @@
+package ics
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	// Base des fuseaux horaires embarquée, pour les TZID des fichiers importés
+	_ "time/tzdata"
+)
+
+// ErrNoCalendar est retourné quand le fichier ne contient pas de VCALENDAR
+var ErrNoCalendar = errors.New("Le fichier n'est pas un calendrier iCalendar")
+
+// ParseError est une erreur de lecture d'un événement. Index est la position de
+// l'événement dans le fichier, à partir de 1.
+type ParseError struct {
+	Index int
+	UID   string
+	Err   error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("événement %d : %v", e.Index, e.Err)
+}
+
+// property est une ligne de contenu : NOM;PARAM=valeur:VALEUR
+type property struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// Parse lit les événements (VEVENT) d'un calendrier. Les dates sont interprétées dans le
+// fuseau TZID s'il est connu, en UTC si elles se terminent par Z et sinon en heure locale
+// flottante. Les événements illisibles sont retournés sous forme de ParseError, sans
+// interrompre la lecture des suivants.
+func Parse(r io.Reader) ([]Event, []*ParseError, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var events []Event
+	var failures []*ParseError
+	var current []property
+	inCalendar, inEvent := false, false
+	// Profondeur des composants imbriqués dans l'événement (VALARM...), ignorés
+	nested := 0
+	index := 0
+
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		prop, err := parseLine(line)
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case prop.Name == "BEGIN" && strings.EqualFold(prop.Value, "VCALENDAR"):
+			inCalendar = true
+		case prop.Name == "BEGIN" && strings.EqualFold(prop.Value, "VEVENT") && inCalendar && !inEvent:
+			inEvent, current, nested = true, nil, 0
+			index++
+		case prop.Name == "BEGIN" && inEvent:
+			nested++
+		case prop.Name == "END" && inEvent && nested > 0:
+			nested--
+		case prop.Name == "END" && strings.EqualFold(prop.Value, "VEVENT") && inEvent:
+			inEvent = false
+			event, err := buildEvent(current)
+			if err != nil {
+				failures = append(failures, &ParseError{Index: index, UID: event.UID, Err: err})
+			} else {
+				events = append(events, event)
+			}
+		case inEvent && nested == 0:
+			current = append(current, prop)
+		}
+	}
+
+	if !inCalendar {
+		return nil, nil, ErrNoCalendar
+	}
+	return events, failures, nil
+}
+
+// unfold lit les lignes en recollant les lignes repliées (commençant par une espace ou une tabulation)
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// parseLine découpe une ligne de contenu en nom, paramètres et valeur.
+// Les deux-points et points-virgules entre guillemets font partie des paramètres.
+func parseLine(line string) (property, error) {
+	prop := property{Params: map[string]string{}}
+
+	quoted := false
+	separator := -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			separator = i
+			break
+		}
+	}
+	if separator < 0 {
+		return prop, errors.New("ligne sans valeur")
+	}
+
+	prop.Value = line[separator+1:]
+	parts := splitUnquoted(line[:separator], ';')
+	prop.Name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		if key, value, ok := strings.Cut(param, "="); ok {
+			prop.Params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return prop, nil
+}
+
+func splitUnquoted(value string, sep rune) []string {
+	var parts []string
+	quoted := false
+	start := 0
+	for i, r := range value {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == sep && !quoted {
+			parts = append(parts, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, value[start:])
+}
+
+// buildEvent construit un événement à partir de ses propriétés
+func buildEvent(props []property) (Event, error) {
+	var event Event
+	var start, end *property
+	var duration string
+	for i := range props {
+		prop := &props[i]
+		switch prop.Name {
+		case "UID":
+			event.UID = prop.Value
+		case "SUMMARY":
+			event.Summary = unescapeText(prop.Value)
+		case "DESCRIPTION":
+			event.Description = unescapeText(prop.Value)
+		case "LOCATION":
+			event.Location = unescapeText(prop.Value)
+		case "URL":
+			event.URL = prop.Value
+		case "DTSTART":
+			start = prop
+		case "DTEND":
+			end = prop
+		case "DURATION":
+			duration = prop.Value
+		case "GEO":
+			lat, lng, ok := parseGeo(prop.Value)
+			if ok {
+				event.HasGeo, event.Latitude, event.Longitude = true, lat, lng
+			}
+		}
+	}
+
+	if start == nil {
+		return event, errors.New("DTSTART manquant")
+	}
+	var err error
+	event.Start, event.AllDay, err = parseDate(start)
+	if err != nil {
+		return event, fmt.Errorf("DTSTART invalide : %v", err)
+	}
+
+	switch {
+	case end != nil:
+		if event.End, _, err = parseDate(end); err != nil {
+			return event, fmt.Errorf("DTEND invalide : %v", err)
+		}
+	case duration != "":
+		d, err := parseDuration(duration)
+		if err != nil {
+			return event, fmt.Errorf("DURATION invalide : %v", err)
+		}
+		event.End = event.Start.Add(d)
+	case event.AllDay:
+		// Sans fin, un événement sur une journée entière dure un jour
+		event.End = event.Start.AddDate(0, 0, 1)
+	default:
+		event.End = event.Start
+	}
+	if event.End.Before(event.Start) {
+		return event, errors.New("DTEND est antérieur à DTSTART")
+	}
+
+	// Pour les journées entières, End est le dernier jour inclus alors que DTEND est exclusif
+	if event.AllDay && event.End.After(event.Start) {
+		event.End = event.End.AddDate(0, 0, -1)
+	}
+	return event, nil
+}
+
+// parseDate lit une propriété DATE ou DATE-TIME et indique s'il s'agit d'une journée entière
+func parseDate(prop *property) (time.Time, bool, error) {
+	value := strings.TrimSpace(prop.Value)
+	if strings.EqualFold(prop.Params["VALUE"], "DATE") || len(value) == len(dateFormat) {
+		t, err := time.ParseInLocation(dateFormat, value, time.Local)
+		return t, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(dateTimeFormat, strings.TrimSuffix(value, "Z"))
+		return t, false, err
+	}
+
+	location := time.Local
+	if tzid := prop.Params["TZID"]; tzid != "" {
+		// Les fuseaux non reconnus (noms Windows par exemple) sont traités en heure flottante
+		if loc, err := time.LoadLocation(strings.TrimPrefix(tzid, "/")); err == nil {
+			location = loc
+		}
+	}
+	t, err := time.ParseInLocation(dateTimeFormat, value, location)
+	return t, false, err
+}
+
+var durationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseDuration lit une durée RFC 5545 (P1D, PT2H30M, P1W...)
+func parseDuration(value string) (time.Duration, error) {
+	match := durationPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil || value == "P" || value == "PT" {
+		return 0, errors.New("format de durée inconnu")
+	}
+
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if match[i+2] == "" {
+			continue
+		}
+		n, _ := strconv.Atoi(match[i+2])
+		d += time.Duration(n) * unit
+	}
+	if match[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
+
+// parseGeo lit une propriété GEO au format latitude;longitude
+func parseGeo(value string) (float64, float64, bool) {
+	latValue, lngValue, ok := strings.Cut(value, ";")
+	if !ok {
+		return 0, 0, false
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latValue), 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	lng, err := strconv.ParseFloat(strings.TrimSpace(lngValue), 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return lat, lng, true
+}
+
+// unescapeText décode une valeur de type TEXT
+func unescapeText(value string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range value {
+		if escaped {
+			switch r {
+			case 'n', 'N':
+				b.WriteRune('\n')
+			default:
+				b.WriteRune(r)
+			}
+			escaped = false
+			continue
+		}
+		if r == '\\' {
+			escaped = true
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
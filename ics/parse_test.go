@@ -0,0 +1,200 @@
+package ics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// calendar assemble un calendrier à partir de lignes de contenu, séparées par CRLF
+func calendar(lines ...string) string {
+	return strings.Join(append(append([]string{"BEGIN:VCALENDAR", "VERSION:2.0"}, lines...), "END:VCALENDAR"), "\r\n") + "\r\n"
+}
+
+func TestParse(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    Event
+		wantErr string
+	}{
+		{
+			name: "folded lines and escaped text",
+			input: calendar(
+				"BEGIN:VEVENT",
+				"UID:fold@test",
+				"SUMMARY:Visite du musée\\, puis dîner",
+				"DESCRIPTION:Première ligne\\nseconde ",
+				" ligne repliée",
+				"LOCATION:Lyo",
+				"\tn",
+				"DTSTART:20250102T090000Z",
+				"DTEND:20250102T110000Z",
+				"END:VEVENT",
+			),
+			want: Event{
+				UID:         "fold@test",
+				Summary:     "Visite du musée, puis dîner",
+				Description: "Première ligne\nseconde ligne repliée",
+				Location:    "Lyon",
+				Start:       time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC),
+				End:         time.Date(2025, 1, 2, 11, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "TZID",
+			input: calendar(
+				"BEGIN:VEVENT",
+				"DTSTART;TZID=Europe/Paris:20250702T093000",
+				"DTEND;TZID=\"Europe/Paris\":20250702T120000",
+				"END:VEVENT",
+			),
+			want: Event{
+				Start: time.Date(2025, 7, 2, 9, 30, 0, 0, paris),
+				End:   time.Date(2025, 7, 2, 12, 0, 0, 0, paris),
+			},
+		},
+		{
+			name: "unknown TZID is floating time",
+			input: calendar(
+				"BEGIN:VEVENT",
+				"DTSTART;TZID=Romance Standard Time:20250702T093000",
+				"END:VEVENT",
+			),
+			want: Event{
+				Start: time.Date(2025, 7, 2, 9, 30, 0, 0, time.Local),
+				End:   time.Date(2025, 7, 2, 9, 30, 0, 0, time.Local),
+			},
+		},
+		{
+			name: "DURATION",
+			input: calendar(
+				"BEGIN:VEVENT",
+				"DTSTART:20250102T090000Z",
+				"DURATION:P1DT2H30M",
+				"END:VEVENT",
+			),
+			want: Event{
+				Start: time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC),
+				End:   time.Date(2025, 1, 3, 11, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "all-day DTEND is exclusive",
+			input: calendar(
+				"BEGIN:VEVENT",
+				"DTSTART;VALUE=DATE:20250102",
+				"DTEND;VALUE=DATE:20250105",
+				"END:VEVENT",
+			),
+			want: Event{
+				Start:  time.Date(2025, 1, 2, 0, 0, 0, 0, time.Local),
+				End:    time.Date(2025, 1, 4, 0, 0, 0, 0, time.Local),
+				AllDay: true,
+			},
+		},
+		{
+			name: "all-day without end lasts one day",
+			input: calendar(
+				"BEGIN:VEVENT",
+				"DTSTART:20250102",
+				"END:VEVENT",
+			),
+			want: Event{
+				Start:  time.Date(2025, 1, 2, 0, 0, 0, 0, time.Local),
+				End:    time.Date(2025, 1, 2, 0, 0, 0, 0, time.Local),
+				AllDay: true,
+			},
+		},
+		{
+			name: "GEO and nested VALARM",
+			input: calendar(
+				"BEGIN:VEVENT",
+				"DTSTART:20250102T090000Z",
+				"GEO:45.764;4.8357",
+				"BEGIN:VALARM",
+				"SUMMARY:Rappel",
+				"END:VALARM",
+				"END:VEVENT",
+			),
+			want: Event{
+				Start:     time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC),
+				End:       time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC),
+				HasGeo:    true,
+				Latitude:  45.764,
+				Longitude: 4.8357,
+			},
+		},
+		{
+			name:    "missing DTSTART",
+			input:   calendar("BEGIN:VEVENT", "UID:nostart@test", "END:VEVENT"),
+			wantErr: "DTSTART manquant",
+		},
+		{
+			name:    "invalid DURATION",
+			input:   calendar("BEGIN:VEVENT", "DTSTART:20250102T090000Z", "DURATION:2 hours", "END:VEVENT"),
+			wantErr: "DURATION invalide",
+		},
+		{
+			name:    "DTEND before DTSTART",
+			input:   calendar("BEGIN:VEVENT", "DTSTART:20250102T090000Z", "DTEND:20250101T090000Z", "END:VEVENT"),
+			wantErr: "DTEND est antérieur à DTSTART",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, failures, err := Parse(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if tt.wantErr != "" {
+				if len(events) != 0 || len(failures) != 1 || !strings.Contains(failures[0].Error(), tt.wantErr) {
+					t.Fatalf("Parse() = %+v, %v, want one failure %q", events, failures, tt.wantErr)
+				}
+				return
+			}
+			if len(failures) != 0 || len(events) != 1 {
+				t.Fatalf("Parse() = %+v, %v, want one event", events, failures)
+			}
+			got := events[0]
+			if !got.Start.Equal(tt.want.Start) || !got.End.Equal(tt.want.End) {
+				t.Errorf("Start, End = %v, %v, want %v, %v", got.Start, got.End, tt.want.Start, tt.want.End)
+			}
+			got.Start, got.End = tt.want.Start, tt.want.End
+			if got != tt.want {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseKeepsReadingAfterAFailure(t *testing.T) {
+	input := calendar(
+		"BEGIN:VEVENT", "UID:bad@test", "END:VEVENT",
+		"BEGIN:VEVENT", "UID:good@test", "DTSTART:20250102T090000Z", "END:VEVENT",
+	)
+	events, failures, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].UID != "good@test" {
+		t.Errorf("events = %+v, want good@test", events)
+	}
+	if len(failures) != 1 || failures[0].Index != 1 || failures[0].UID != "bad@test" {
+		t.Errorf("failures = %+v, want event 1 bad@test", failures)
+	}
+}
+
+func TestParseRequiresCalendar(t *testing.T) {
+	_, _, err := Parse(strings.NewReader("BEGIN:VEVENT\r\nDTSTART:20250102\r\nEND:VEVENT\r\n"))
+	if !errors.Is(err, ErrNoCalendar) {
+		t.Fatalf("Parse() error = %v, want ErrNoCalendar", err)
+	}
+}
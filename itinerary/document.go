@@ -0,0 +1,45 @@
+package itinerary
+
+import "time"
+
+// Document est le contenu d'un carnet de voyage imprimable. Les dates nulles
+// (time.Time{}) sont considérées comme inconnues et ne sont pas affichées.
+type Document struct {
+	Title       string
+	Location    string
+	Description string
+	Notes       string
+	Start       time.Time
+	End         time.Time
+	HasPosition bool
+	Latitude    float64
+	Longitude   float64
+	Days        []Day
+	Expenses    []Expense
+}
+
+// Day est une journée du programme
+type Day struct {
+	Date  time.Time
+	Title string
+	Notes string
+	Stops []Stop
+}
+
+// Stop est une étape d'une journée ; les heures sont au format HH:MM, facultatives
+type Stop struct {
+	Name      string
+	StartTime string
+	EndTime   string
+	Notes     string
+}
+
+// Expense est une dépense du voyage
+type Expense struct {
+	Date     time.Time
+	Category string
+	Note     string
+	Payer    string
+	Amount   float64
+	Currency string
+}
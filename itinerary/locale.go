@@ -0,0 +1,183 @@
+package itinerary
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Locale regroupe les libellés et les règles de formatage d'une langue
+type Locale struct {
+	Tag      string
+	months   [12]string
+	weekdays [7]string
+	labels   map[string]string
+	// Séparateurs des milliers et des décimales des montants
+	thousands string
+	decimal   string
+	// dayFirst place le jour avant le mois (« 2 mai 2026 » plutôt que « May 2, 2026 »)
+	dayFirst bool
+}
+
+// French est la langue par défaut des documents
+var French = &Locale{
+	Tag:       "fr",
+	months:    [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+	weekdays:  [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+	thousands: " ",
+	decimal:   ",",
+	dayFirst:  true,
+	labels: map[string]string{
+		"dates":       "Dates",
+		"from":        "Du %s au %s",
+		"on":          "Le %s",
+		"location":    "Lieu",
+		"map":         "Carte",
+		"noPosition":  "Position non renseignée",
+		"description": "Description",
+		"notes":       "Notes",
+		"program":     "Programme",
+		"day":         "Jour %d",
+		"dayTitle":    "%s : %s",
+		"allDay":      "Journée",
+		"expenses":    "Dépenses",
+		"date":        "Date",
+		"category":    "Catégorie",
+		"detail":      "Détail",
+		"payer":       "Payé par",
+		"amount":      "Montant",
+		"total":       "Total",
+		"page":        "Page %d/%s",
+	},
+}
+
+// English est la traduction anglaise des documents
+var English = &Locale{
+	Tag:       "en",
+	months:    [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+	weekdays:  [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+	thousands: ",",
+	decimal:   ".",
+	labels: map[string]string{
+		"dates":       "Dates",
+		"from":        "From %s to %s",
+		"on":          "On %s",
+		"location":    "Location",
+		"map":         "Map",
+		"noPosition":  "No position set",
+		"description": "Description",
+		"notes":       "Notes",
+		"program":     "Itinerary",
+		"day":         "Day %d",
+		"dayTitle":    "%s: %s",
+		"allDay":      "All day",
+		"expenses":    "Expenses",
+		"date":        "Date",
+		"category":    "Category",
+		"detail":      "Details",
+		"payer":       "Paid by",
+		"amount":      "Amount",
+		"total":       "Total",
+		"page":        "Page %d/%s",
+	},
+}
+
+var locales = map[string]*Locale{"fr": French, "en": English}
+
+// LookupLocale retourne la langue correspondant à un code (« fr », « en-GB »...)
+func LookupLocale(tag string) (*Locale, bool) {
+	primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	locale, ok := locales[primary]
+	return locale, ok
+}
+
+// NegotiateLocale choisit la langue préférée parmi celles d'un en-tête Accept-Language,
+// en tenant compte des poids q. Le français est retenu si aucune ne correspond.
+func NegotiateLocale(header string) *Locale {
+	type candidate struct {
+		locale *Locale
+		q      float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		locale, ok := LookupLocale(tag)
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{locale, q})
+		}
+	}
+	if len(candidates) == 0 {
+		return French
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].locale
+}
+
+// Label retourne le libellé traduit d'une clé
+func (l *Locale) Label(key string) string {
+	if label, ok := l.labels[key]; ok {
+		return label
+	}
+	return key
+}
+
+// LongDate formate une date avec le jour de la semaine : « samedi 2 mai 2026 », « Saturday, May 2, 2026 »
+func (l *Locale) LongDate(t time.Time) string {
+	if l.dayFirst {
+		return fmt.Sprintf("%s %s", l.weekdays[t.Weekday()], l.Date(t))
+	}
+	return fmt.Sprintf("%s, %s", l.weekdays[t.Weekday()], l.Date(t))
+}
+
+// Date formate une date sans le jour de la semaine : « 2 mai 2026 », « May 2, 2026 »
+func (l *Locale) Date(t time.Time) string {
+	month := l.months[t.Month()-1]
+	if l.dayFirst {
+		day := strconv.Itoa(t.Day())
+		if t.Day() == 1 {
+			day = "1er"
+		}
+		return fmt.Sprintf("%s %s %d", day, month, t.Year())
+	}
+	return fmt.Sprintf("%s %d, %d", month, t.Day(), t.Year())
+}
+
+// ShortDate formate une date en chiffres : 02/05/2026 en français, 05/02/2026 en anglais
+func (l *Locale) ShortDate(t time.Time) string {
+	if l.dayFirst {
+		return t.Format("02/01/2006")
+	}
+	return t.Format("01/02/2006")
+}
+
+// Amount formate un montant avec deux décimales et sa devise : « 1 234,50 EUR »
+func (l *Locale) Amount(amount float64, currency string) string {
+	cents := int64(math.Round(math.Abs(amount) * 100))
+	units := strconv.FormatInt(cents/100, 10)
+
+	var grouped strings.Builder
+	for i, digit := range units {
+		if i > 0 && (len(units)-i)%3 == 0 {
+			grouped.WriteString(l.thousands)
+		}
+		grouped.WriteRune(digit)
+	}
+
+	sign := ""
+	if amount < 0 && cents > 0 {
+		sign = "-"
+	}
+	return fmt.Sprintf("%s%s%s%02d %s", sign, grouped.String(), l.decimal, cents%100, currency)
+}
@@ -0,0 +1,286 @@
+package itinerary
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// Mise en page A4 portrait, en millimètres
+const (
+	pageMargin  = 15
+	contentW    = 210 - 2*pageMargin
+	lineHeight  = 5.5
+	mapHeight   = 45
+	fontFamily  = "Helvetica"
+	creatorName = "TravelMate"
+)
+
+// Largeurs des colonnes du tableau des dépenses : date, catégorie, détail, payé par, montant
+var expenseColumns = [5]float64{24, 32, 62, 30, 32}
+
+// renderer écrit un document avec les polices standard du PDF, qui utilisent
+// l'encodage cp1252 : les textes UTF-8 sont convertis par tr
+type renderer struct {
+	pdf    *fpdf.Fpdf
+	tr     func(string) string
+	locale *Locale
+}
+
+// WritePDF écrit le carnet de voyage au format PDF dans la langue donnée
+func WritePDF(w io.Writer, doc *Document, locale *Locale) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	r := &renderer{pdf: pdf, tr: pdf.UnicodeTranslatorFromDescriptor(""), locale: locale}
+
+	pdf.SetMargins(pageMargin, pageMargin, pageMargin)
+	pdf.SetAutoPageBreak(true, pageMargin+5)
+	pdf.SetTitle(doc.Title, true)
+	pdf.SetCreator(creatorName, true)
+	pdf.SetLang(locale.Tag)
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(r.footer)
+	pdf.AddPage()
+
+	r.header(doc)
+	r.mapPlaceholder(doc)
+	r.paragraph(locale.Label("description"), doc.Description)
+	r.paragraph(locale.Label("notes"), doc.Notes)
+	r.program(doc.Days)
+	r.expenses(doc.Expenses)
+
+	return pdf.Output(w)
+}
+
+func (r *renderer) footer() {
+	r.pdf.SetY(-pageMargin)
+	r.pdf.SetFont(fontFamily, "I", 8)
+	r.pdf.SetTextColor(120, 120, 120)
+	r.pdf.CellFormat(0, 5, r.tr(fmt.Sprintf(r.locale.Label("page"), r.pdf.PageNo(), "{nb}")), "", 0, "C", false, 0, "")
+	r.pdf.SetTextColor(0, 0, 0)
+}
+
+// header écrit le titre, les dates et le lieu du voyage
+func (r *renderer) header(doc *Document) {
+	r.pdf.SetFont(fontFamily, "B", 20)
+	r.pdf.MultiCell(0, 9, r.tr(doc.Title), "", "L", false)
+	r.pdf.Ln(2)
+
+	if dates := r.dateRange(doc); dates != "" {
+		r.field(r.locale.Label("dates"), dates)
+	}
+	if doc.Location != "" {
+		r.field(r.locale.Label("location"), doc.Location)
+	}
+	r.pdf.Ln(3)
+}
+
+func (r *renderer) dateRange(doc *Document) string {
+	switch {
+	case doc.Start.IsZero():
+		return ""
+	case doc.End.IsZero() || doc.End.Equal(doc.Start):
+		return fmt.Sprintf(r.locale.Label("on"), r.locale.LongDate(doc.Start))
+	default:
+		return fmt.Sprintf(r.locale.Label("from"), r.locale.LongDate(doc.Start), r.locale.LongDate(doc.End))
+	}
+}
+
+func (r *renderer) field(label, value string) {
+	r.pdf.SetFont(fontFamily, "B", 11)
+	r.pdf.CellFormat(25, lineHeight+1, r.tr(label), "", 0, "L", false, 0, "")
+	r.pdf.SetFont(fontFamily, "", 11)
+	r.pdf.MultiCell(0, lineHeight+1, r.tr(value), "", "L", false)
+}
+
+// mapPlaceholder réserve l'emplacement de la vignette de carte et y indique la position du voyage
+func (r *renderer) mapPlaceholder(doc *Document) {
+	x, y := r.pdf.GetX(), r.pdf.GetY()
+	r.pdf.SetFillColor(235, 240, 245)
+	r.pdf.SetDrawColor(180, 190, 200)
+	r.pdf.Rect(x, y, contentW, mapHeight, "FD")
+
+	r.pdf.SetTextColor(90, 100, 110)
+	r.pdf.SetFont(fontFamily, "B", 12)
+	r.pdf.SetXY(x, y+mapHeight/2-7)
+	r.pdf.CellFormat(contentW, 7, r.tr(r.locale.Label("map")), "", 2, "C", false, 0, "")
+	r.pdf.SetFont(fontFamily, "", 10)
+	position := r.locale.Label("noPosition")
+	if doc.HasPosition {
+		position = r.coordinates(doc.Latitude, doc.Longitude)
+	}
+	r.pdf.CellFormat(contentW, 6, r.tr(position), "", 2, "C", false, 0, "")
+
+	r.pdf.SetTextColor(0, 0, 0)
+	r.pdf.SetDrawColor(0, 0, 0)
+	r.pdf.SetXY(x, y+mapHeight+6)
+}
+
+// coordinates formate une position en degrés décimaux : « 48,85660° N, 2,35220° E »
+func (r *renderer) coordinates(lat, lng float64) string {
+	latSide, lngSide := "N", "E"
+	if lat < 0 {
+		latSide = "S"
+	}
+	if lng < 0 {
+		lngSide = "W"
+	}
+	format := func(value float64) string {
+		return strings.Replace(fmt.Sprintf("%.5f", math.Abs(value)), ".", r.locale.decimal, 1)
+	}
+	return fmt.Sprintf("%s° %s, %s° %s", format(lat), latSide, format(lng), lngSide)
+}
+
+func (r *renderer) section(title string) {
+	// Un titre de section n'est pas laissé seul en bas de page
+	_, pageH := r.pdf.GetPageSize()
+	if r.pdf.GetY() > pageH-pageMargin-30 {
+		r.pdf.AddPage()
+	}
+	r.pdf.SetFont(fontFamily, "B", 14)
+	r.pdf.SetTextColor(30, 70, 120)
+	r.pdf.CellFormat(0, 8, r.tr(title), "B", 1, "L", false, 0, "")
+	r.pdf.SetTextColor(0, 0, 0)
+	r.pdf.Ln(2)
+}
+
+func (r *renderer) paragraph(title, text string) {
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+	r.section(title)
+	r.pdf.SetFont(fontFamily, "", 11)
+	r.pdf.MultiCell(0, lineHeight, r.tr(text), "", "L", false)
+	r.pdf.Ln(4)
+}
+
+// program écrit le programme jour par jour et ses étapes
+func (r *renderer) program(days []Day) {
+	if len(days) == 0 {
+		return
+	}
+	r.section(r.locale.Label("program"))
+
+	for i, day := range days {
+		heading := fmt.Sprintf(r.locale.Label("day"), i+1)
+		if !day.Date.IsZero() {
+			heading += " - " + r.locale.LongDate(day.Date)
+		}
+		if day.Title != "" {
+			heading = fmt.Sprintf(r.locale.Label("dayTitle"), heading, day.Title)
+		}
+		r.pdf.SetFont(fontFamily, "B", 12)
+		r.pdf.MultiCell(0, 7, r.tr(heading), "", "L", false)
+
+		if day.Notes != "" {
+			r.pdf.SetFont(fontFamily, "I", 10)
+			r.pdf.MultiCell(0, lineHeight, r.tr(day.Notes), "", "L", false)
+		}
+		for _, stop := range day.Stops {
+			r.stop(stop)
+		}
+		r.pdf.Ln(3)
+	}
+}
+
+func (r *renderer) stop(stop Stop) {
+	hours := r.locale.Label("allDay")
+	switch {
+	case stop.StartTime != "" && stop.EndTime != "":
+		hours = stop.StartTime + " - " + stop.EndTime
+	case stop.StartTime != "":
+		hours = stop.StartTime
+	}
+
+	r.pdf.SetFont(fontFamily, "", 10)
+	r.pdf.SetTextColor(90, 100, 110)
+	r.pdf.CellFormat(28, lineHeight, r.tr(hours), "", 0, "L", false, 0, "")
+	r.pdf.SetTextColor(0, 0, 0)
+	r.pdf.SetFont(fontFamily, "B", 10)
+	r.pdf.MultiCell(0, lineHeight, r.tr(stop.Name), "", "L", false)
+
+	if stop.Notes != "" {
+		r.pdf.SetX(pageMargin + 28)
+		r.pdf.SetFont(fontFamily, "", 9)
+		r.pdf.MultiCell(0, lineHeight-1, r.tr(stop.Notes), "", "L", false)
+	}
+}
+
+// expenses écrit le tableau des dépenses et leur total par devise
+func (r *renderer) expenses(expenses []Expense) {
+	if len(expenses) == 0 {
+		return
+	}
+	r.section(r.locale.Label("expenses"))
+
+	r.expenseHeader()
+	totals := map[string]float64{}
+	var currencies []string
+	for _, expense := range expenses {
+		_, pageH := r.pdf.GetPageSize()
+		if r.pdf.GetY()+lineHeight+1 > pageH-pageMargin-5 {
+			r.pdf.AddPage()
+			r.expenseHeader()
+		}
+
+		date := ""
+		if !expense.Date.IsZero() {
+			date = r.locale.ShortDate(expense.Date)
+		}
+		cells := [5]string{date, expense.Category, expense.Note, expense.Payer, r.locale.Amount(expense.Amount, expense.Currency)}
+		r.pdf.SetFont(fontFamily, "", 9)
+		for i, text := range cells {
+			align := "L"
+			if i == len(cells)-1 {
+				align = "R"
+			}
+			r.pdf.CellFormat(expenseColumns[i], lineHeight+1, r.fit(text, expenseColumns[i]-2), "B", 0, align, false, 0, "")
+		}
+		r.pdf.Ln(-1)
+
+		if _, ok := totals[expense.Currency]; !ok {
+			currencies = append(currencies, expense.Currency)
+		}
+		totals[expense.Currency] += expense.Amount
+	}
+
+	r.pdf.SetFont(fontFamily, "B", 10)
+	labelW := contentW - expenseColumns[len(expenseColumns)-1]
+	for _, currency := range currencies {
+		r.pdf.CellFormat(labelW, lineHeight+1, r.tr(r.locale.Label("total")), "", 0, "R", false, 0, "")
+		r.pdf.CellFormat(expenseColumns[len(expenseColumns)-1], lineHeight+1, r.tr(r.locale.Amount(totals[currency], currency)), "", 1, "R", false, 0, "")
+	}
+}
+
+func (r *renderer) expenseHeader() {
+	labels := [5]string{"date", "category", "detail", "payer", "amount"}
+	r.pdf.SetFont(fontFamily, "B", 9)
+	r.pdf.SetFillColor(235, 240, 245)
+	for i, key := range labels {
+		align := "L"
+		if i == len(labels)-1 {
+			align = "R"
+		}
+		r.pdf.CellFormat(expenseColumns[i], lineHeight+1, r.tr(r.locale.Label(key)), "B", 0, align, true, 0, "")
+	}
+	r.pdf.Ln(-1)
+}
+
+// fit convertit le texte et le raccourcit pour qu'il tienne sur une largeur donnée
+func (r *renderer) fit(text string, width float64) string {
+	converted := r.tr(text)
+	if r.pdf.GetStringWidth(converted) <= width {
+		return converted
+	}
+	runes := []rune(text)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		converted = r.tr(strings.TrimSpace(string(runes)) + "…")
+		if r.pdf.GetStringWidth(converted) <= width {
+			break
+		}
+	}
+	return converted
+}
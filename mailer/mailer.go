@@ -0,0 +1,87 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"travelmate-api/logger"
+)
+
+// Message est un email à envoyer
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender est implémenté par chaque moyen d'envoi d'emails
+type Sender interface {
+	Send(msg Message) error
+}
+
+// DefaultSender est utilisé par Send ; il est choisi par InitMailer selon la configuration
+var DefaultSender Sender = &FileSender{Path: "logs/mail.log"}
+
+// InitMailer choisit le moyen d'envoi selon la variable MAIL_DRIVER : file (par défaut)
+// ou smtp, configuré par SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD et MAIL_FROM
+func InitMailer() {
+	switch os.Getenv("MAIL_DRIVER") {
+	case "", "file":
+		path := os.Getenv("MAIL_FILE")
+		if path == "" {
+			path = "logs/mail.log"
+		}
+		DefaultSender = &FileSender{Path: path}
+	case "smtp":
+		DefaultSender = &SMTPSender{
+			Host:     getenv("SMTP_HOST", "localhost"),
+			Port:     getenv("SMTP_PORT", "1025"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     getenv("MAIL_FROM", "TravelMate <no-reply@travelmate.local>"),
+		}
+	default:
+		logger.ErrorLogger.Printf("MAIL_DRIVER inconnu (%s), les emails seront écrits dans logs/mail.log", os.Getenv("MAIL_DRIVER"))
+		DefaultSender = &FileSender{Path: "logs/mail.log"}
+	}
+}
+
+func getenv(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// Send envoie un email avec le moyen d'envoi configuré
+func Send(msg Message) error {
+	return DefaultSender.Send(msg)
+}
+
+// FileSender écrit les emails dans un fichier au lieu de les envoyer, pour le développement local
+type FileSender struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func (s *FileSender) Send(msg Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintf(file, "Date: %s\nTo: %s\nSubject: %s\n\n%s\n\n----------\n\n",
+		time.Now().Format(time.RFC1123Z), msg.To, msg.Subject, msg.Body)
+	if err != nil {
+		return err
+	}
+
+	logger.InfoLogger.Printf("Email \"%s\" écrit dans %s pour %s", msg.Subject, s.Path, msg.To)
+	return nil
+}
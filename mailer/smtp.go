@@ -0,0 +1,54 @@
+package mailer
+
+import (
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"travelmate-api/logger"
+)
+
+// SMTPSender envoie les emails par un serveur SMTP. En développement, il peut viser un
+// serveur de test qui capture les messages (MailHog, Mailpit...) sur le port 1025.
+type SMTPSender struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+func (s *SMTPSender) Send(msg Message) error {
+	from, err := mail.ParseAddress(s.From)
+	if err != nil {
+		return fmt.Errorf("adresse d'expédition invalide : %v", err)
+	}
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+
+	headers := []string{
+		"From: " + from.String(),
+		"To: " + msg.To,
+		"Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=utf-8",
+		"Content-Transfer-Encoding: 8bit",
+	}
+	body := strings.ReplaceAll(msg.Body, "\n", "\r\n")
+	data := []byte(strings.Join(headers, "\r\n") + "\r\n\r\n" + body + "\r\n")
+
+	if err := smtp.SendMail(net.JoinHostPort(s.Host, s.Port), auth, from.Address, []string{msg.To}, data); err != nil {
+		return err
+	}
+
+	logger.InfoLogger.Printf("Email \"%s\" envoyé par %s à %s", msg.Subject, s.Host, msg.To)
+	return nil
+}
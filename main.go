@@ -2,10 +2,15 @@ package main
 
 import (
 	"log"
+	"os"
+	"strings"
 	"time"
 	"travelmate-api/database"
 	"travelmate-api/logger"
+	"travelmate-api/mailer"
 	"travelmate-api/routes"
+	"travelmate-api/sso"
+	"travelmate-api/utils"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -24,16 +29,39 @@ func main() {
         log.Println("Pas de .env détecté")
     }
 
+    // On charge les clés de signature des jetons
+    if err := utils.InitKeys(); err != nil {
+        log.Fatalf("Clés de signature des jetons : %v", err)
+    }
+
+    // On choisit le moyen d'envoi des emails
+    mailer.InitMailer()
+
+    // On charge les fournisseurs d'identité OpenID Connect
+    sso.InitProviders()
+
 	// On créé la BDD
 
     database.InitDB()
     r := gin.Default()
 
+    // On ne fait confiance à X-Forwarded-For que derrière les proxys listés dans TRUSTED_PROXIES
+    // (IP ou CIDR séparés par des virgules) : sinon l'adresse IP du client est celle de la connexion
+    var trustedProxies []string
+    if proxies := os.Getenv("TRUSTED_PROXIES"); proxies != "" {
+        for _, proxy := range strings.Split(proxies, ",") {
+            trustedProxies = append(trustedProxies, strings.TrimSpace(proxy))
+        }
+    }
+    if err := r.SetTrustedProxies(trustedProxies); err != nil {
+        log.Fatalf("TRUSTED_PROXIES : %v", err)
+    }
+
     r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"}, 
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		ExposeHeaders:    []string{"Content-Length", "X-Total-Count", "Link"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"travelmate-api/database"
+	"travelmate-api/models"
+	"travelmate-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Scopes des jetons d'accès personnels
+const (
+	// ScopeTripsRead permet de consulter les voyages, leur programme, leurs dépenses et les invitations reçues
+	ScopeTripsRead = "trips:read"
+	// ScopeTripsWrite permet de créer, modifier, supprimer et partager des voyages
+	ScopeTripsWrite = "trips:write"
+	// ScopeUsersRead permet de consulter son profil et de rechercher des utilisateurs
+	ScopeUsersRead = "users:read"
+)
+
+// APITokenScopes liste les scopes qu'un jeton d'accès personnel peut recevoir
+var APITokenScopes = []string{ScopeTripsRead, ScopeTripsWrite, ScopeUsersRead}
+
+// authenticateAPIToken vérifie un jeton d'accès personnel et place son propriétaire dans le contexte.
+// Un jeton d'API ne donne jamais les privilèges d'administration.
+func authenticateAPIToken(c *gin.Context, token string) bool {
+	var apiToken models.APIToken
+	if err := database.DB.Where("token_hash = ?", utils.HashToken(token)).First(&apiToken).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token invalide"})
+		return false
+	}
+	if apiToken.RevokedAt != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token révoqué"})
+		return false
+	}
+	if time.Now().After(apiToken.ExpiresAt) {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token expiré"})
+		return false
+	}
+	if apiToken.LastUsedAt == nil || time.Since(*apiToken.LastUsedAt) > lastSeenInterval {
+		database.DB.Model(&apiToken).Update("last_used_at", time.Now())
+	}
+
+	c.Set("user_id", apiToken.UserID)
+	c.Set("is_admin", false)
+	c.Set("api_token_id", apiToken.ID)
+	c.Set("api_token_scopes", apiToken.Scopes)
+	c.Set("token_expires_at", apiToken.ExpiresAt)
+	return true
+}
+
+// isAPITokenRequest indique si la requête est authentifiée par un jeton d'accès personnel
+func isAPITokenRequest(c *gin.Context) bool {
+	_, ok := c.Get("api_token_id")
+	return ok
+}
+
+// RequireScope limite l'accès des jetons d'accès personnels aux routes couvertes par leurs
+// scopes : read pour les requêtes GET et HEAD, write pour les autres. Les connexions par
+// mot de passe ne sont pas concernées. À placer après AuthMiddleware.
+func RequireScope(read, write string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isAPITokenRequest(c) {
+			c.Next()
+			return
+		}
+
+		scope := write
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			scope = read
+		}
+		if !slices.Contains(c.GetStringSlice("api_token_scopes"), scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Ce token d'API n'a pas le scope requis : " + scope})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireReadScope réserve un groupe de routes en lecture seule aux jetons d'API ayant le scope
+// indiqué : les autres méthodes que GET et HEAD leur sont refusées. À placer après AuthMiddleware.
+func RequireReadScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isAPITokenRequest(c) {
+			c.Next()
+			return
+		}
+
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Cette action n'est pas disponible avec un token d'API"})
+			return
+		}
+		if !slices.Contains(c.GetStringSlice("api_token_scopes"), scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Ce token d'API n'a pas le scope requis : " + scope})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// SessionOnly réserve une route aux connexions par mot de passe : la gestion du compte
+// (sessions, double authentification, jetons d'API...) est refusée aux jetons d'API.
+// À placer après AuthMiddleware.
+func SessionOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if isAPITokenRequest(c) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Cette action n'est pas disponible avec un token d'API"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -3,79 +3,124 @@ package middleware
 import (
 	"net/http"
 	"strings"
+	"time"
 
+	"travelmate-api/database"
+	"travelmate-api/models"
 	"travelmate-api/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
-var SecretKey = []byte("JWT_SECRET")
+// Intervalle minimal entre deux mises à jour de la dernière activité d'une session
+const lastSeenInterval = time.Minute
+
+// authenticate vérifie le jeton de l'en-tête Authorization (jeton d'accès JWT ou jeton d'accès
+// personnel), refuse les jetons révoqués et place l'utilisateur dans le contexte. La requête
+// est interrompue en cas d'échec.
+func authenticate(c *gin.Context) bool {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token manquant ou invalide"})
+		return false
+	}
+	token := strings.TrimPrefix(authHeader, "Bearer ")
 
-func AuthMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token manquant ou invalide"})
-			return
-		}
-		token := strings.TrimPrefix(authHeader, "Bearer ")
+	if strings.HasPrefix(token, utils.APITokenPrefix) {
+		return authenticateAPIToken(c, token)
+	}
+	return authenticateAccessToken(c, token)
+}
 
-		claims, err := utils.ParseToken(token)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token invalide"})
-			return
-		}
+// authenticateAccessToken vérifie un jeton d'accès JWT et la session à laquelle il appartient
+func authenticateAccessToken(c *gin.Context, token string) bool {
+	claims, err := utils.ParseAccessToken(token)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token invalide"})
+		return false
+	}
 
-		userIDFloat, ok := claims["user_id"].(float64)
-		if !ok {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Identifiant utilisateur manquant"})
-			return
-		}
-		userID := uint(userIDFloat)
+	userIDFloat, ok := claims["user_id"].(float64)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Identifiant utilisateur manquant"})
+		return false
+	}
+	userID := uint(userIDFloat)
+
+	jti := claims["jti"].(string)
+	var revoked int64
+	if err := database.DB.Model(&models.RevokedToken{}).Where("jti = ?", jti).Count(&revoked).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la vérification du token"})
+		return false
+	}
+	if revoked > 0 {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token révoqué"})
+		return false
+	}
 
-		isAdmin, ok := claims["is_admin"].(bool)
-		if !ok {
+	// La session doit être active : la révoquer déconnecte immédiatement l'appareil
+	sessionID, ok := claims["sid"].(float64)
+	var session models.Session
+	if !ok || database.DB.First(&session, uint(sessionID)).Error != nil || session.UserID != userID || session.RevokedAt != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Session expirée ou révoquée"})
+		return false
+	}
+	if time.Since(session.LastSeenAt) > lastSeenInterval || session.IP != c.ClientIP() {
+		database.DB.Model(&session).Updates(map[string]interface{}{"last_seen_at": time.Now(), "ip": c.ClientIP()})
+	}
+
+	isAdmin, ok := claims["is_admin"].(bool)
+	if !ok {
+		isAdmin = false
+	}
+	if isAdmin && AdminTwoFactorRequired() {
+		var user models.User
+		if err := database.DB.Select("id", "two_factor_enabled").First(&user, userID).Error; err != nil || !user.TwoFactorEnabled {
 			isAdmin = false
+			c.Set("admin_2fa_required", true)
 		}
+	}
+	expiresAt, _ := claims.GetExpirationTime()
+
+	c.Set("user_id", userID)
+	c.Set("is_admin", isAdmin)
+	c.Set("jti", jti)
+	c.Set("session_id", session.ID)
+	if expiresAt != nil {
+		c.Set("token_expires_at", expiresAt.Time)
+	}
+	return true
+}
 
-		c.Set("user_id", userID)
-		c.Set("is_admin", isAdmin)
-
+func AuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !authenticate(c) {
+			return
+		}
 		c.Next()
 	}
 }
 
 func IsAdmin() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token manquant ou invalide"})
+		// Sur un groupe déjà protégé par AuthMiddleware, le jeton a déjà été vérifié
+		if _, authenticated := c.Get("user_id"); !authenticated && !authenticate(c) {
 			return
 		}
-		token := strings.TrimPrefix(authHeader, "Bearer ")
 
-		claims, err := utils.ParseToken(token)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token invalide"})
+		if isAPITokenRequest(c) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Les routes d'administration ne sont pas disponibles avec un token d'API"})
 			return
 		}
-
-		userIDFloat, ok := claims["user_id"].(float64)
-		if !ok {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "L'identifiant de l'utilisateur est manquant dans le token"})
+		if c.GetBool("admin_2fa_required") {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "La double authentification est obligatoire pour les administrateurs : activez-la avec POST /me/2fa/setup"})
 			return
 		}
-		userID := uint(userIDFloat)
-
-		isAdmin, ok := claims["is_admin"].(bool)
-		if !ok || !isAdmin {
+		if !c.GetBool("is_admin") {
 			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Accès réservé aux administrateurs"})
 			return
 		}
 
-		c.Set("user_id", userID)
-		c.Set("is_admin", isAdmin)
-
 		c.Next()
 	}
 }
@@ -0,0 +1,14 @@
+package middleware
+
+import (
+	"os"
+	"strconv"
+)
+
+// AdminTwoFactorRequired indique si la double authentification est obligatoire pour les
+// administrateurs (REQUIRE_ADMIN_2FA=true). Tant qu'ils ne l'ont pas activée, leurs
+// privilèges d'administration sont suspendus.
+func AdminTwoFactorRequired() bool {
+	required, _ := strconv.ParseBool(os.Getenv("REQUIRE_ADMIN_2FA"))
+	return required
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"net/http"
+	"os"
+
+	"travelmate-api/database"
+	"travelmate-api/logger"
+	"travelmate-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Actions soumises à la vérification de l'adresse email
+const (
+	// ActionCreate regroupe la création et l'import de voyages
+	ActionCreate = "create"
+	// ActionShare regroupe l'ajout de membres, les invitations et les liens de partage
+	ActionShare = "share"
+)
+
+// Politiques de vérification des emails (EMAIL_VERIFICATION_POLICY)
+const (
+	// VerificationNone n'exige jamais d'email vérifié
+	VerificationNone = "none"
+	// VerificationSharing exige un email vérifié pour partager un voyage (par défaut)
+	VerificationSharing = "sharing"
+	// VerificationAll exige un email vérifié pour créer ou partager un voyage
+	VerificationAll = "all"
+)
+
+// EmailVerificationPolicy retourne la politique configurée par EMAIL_VERIFICATION_POLICY
+func EmailVerificationPolicy() string {
+	switch policy := os.Getenv("EMAIL_VERIFICATION_POLICY"); policy {
+	case VerificationNone, VerificationAll:
+		return policy
+	case "", VerificationSharing:
+		return VerificationSharing
+	default:
+		logger.ErrorLogger.Printf("EMAIL_VERIFICATION_POLICY inconnue (%s), politique %s appliquée", policy, VerificationSharing)
+		return VerificationSharing
+	}
+}
+
+// requiresVerifiedEmail indique si la politique exige un email vérifié pour l'action
+func requiresVerifiedEmail(policy, action string) bool {
+	switch policy {
+	case VerificationAll:
+		return true
+	case VerificationSharing:
+		return action == ActionShare
+	default:
+		return false
+	}
+}
+
+// RequireVerifiedEmail refuse l'action aux utilisateurs dont l'adresse email n'est pas
+// vérifiée, si la politique configurée l'exige. À placer après AuthMiddleware.
+func RequireVerifiedEmail(action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !requiresVerifiedEmail(EmailVerificationPolicy(), action) {
+			c.Next()
+			return
+		}
+
+		var user models.User
+		if err := database.DB.Select("id", "email_verified").First(&user, c.GetUint("user_id")).Error; err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Utilisateur non trouvé"})
+			return
+		}
+		if !user.EmailVerified {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Veuillez vérifier votre adresse email avant de continuer"})
+			return
+		}
+
+		c.Next()
+	}
+}
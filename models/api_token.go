@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// APIToken est un jeton d'accès personnel, destiné aux scripts et intégrations. Il est
+// conservé sous forme d'empreinte et ne donne accès qu'aux routes couvertes par ses scopes.
+type APIToken struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"userId" gorm:"index;not null"`
+	Name       string     `json:"name" gorm:"not null" example:"Synchronisation agenda"`
+	Prefix     string     `json:"prefix" example:"tm_pat_Zx81"`
+	TokenHash  string     `json:"-" gorm:"uniqueIndex;not null"`
+	Scopes     []string   `json:"scopes" gorm:"serializer:json" example:"trips:read"`
+	ExpiresAt  time.Time  `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	Token      string     `json:"token,omitempty" gorm:"-"`
+}
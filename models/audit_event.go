@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Types d'événements d'audit
+const (
+	// AuditLoginLocked : compte verrouillé après trop d'échecs de connexion
+	AuditLoginLocked = "login.locked"
+	// AuditLoginIPLocked : adresse IP bloquée après trop d'échecs de connexion
+	AuditLoginIPLocked = "login.ip_locked"
+	// AuditLoginUnlocked : compte déverrouillé par un administrateur
+	AuditLoginUnlocked = "login.unlocked"
+)
+
+// AuditEvent est un événement de sécurité conservé pour les administrateurs
+type AuditEvent struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Type      string    `json:"type" gorm:"index;not null" example:"login.locked"`
+	UserID    *uint     `json:"userId,omitempty" gorm:"index"`
+	ActorID   *uint     `json:"actorId,omitempty"`
+	Email     string    `json:"email,omitempty" example:"alice@example.com"`
+	IP        string    `json:"ip,omitempty" example:"203.0.113.7"`
+	Details   string    `json:"details,omitempty"`
+	CreatedAt time.Time `json:"createdAt" gorm:"index"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// CalendarFeed est le flux iCalendar privé d'un utilisateur, accessible sans authentification
+// par son jeton secret. Un utilisateur a au plus un flux.
+type CalendarFeed struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"userId" gorm:"uniqueIndex;not null"`
+	TokenHash string    `json:"-" gorm:"uniqueIndex;not null"`
+	CreatedAt time.Time `json:"createdAt"`
+	Token     string    `json:"token,omitempty" gorm:"-"`
+	URL       string    `json:"url,omitempty" gorm:"-"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// EmailVerification est une demande de vérification d'adresse email. Le jeton envoyé à
+// l'adresse n'est conservé que sous forme d'empreinte ; il ne vaut que pour l'adresse
+// Email, qui doit toujours être celle du compte au moment de la vérification.
+type EmailVerification struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"userId" gorm:"index;not null"`
+	Email     string     `json:"email"`
+	TokenHash string     `json:"-" gorm:"uniqueIndex;not null"`
+	ExpiresAt time.Time  `json:"expiresAt"`
+	UsedAt    *time.Time `json:"usedAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
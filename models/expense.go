@@ -0,0 +1,106 @@
+package models
+
+import "time"
+
+// Modes de répartition d'une dépense entre les participants
+const (
+	SplitEqual  = "equal"
+	SplitShares = "shares"
+	SplitExact  = "exact"
+)
+
+// Expense représente une dépense effectuée dans le cadre d'un voyage
+type Expense struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	TripID    uint           `json:"tripId" gorm:"index;not null"`
+	Amount    float64        `json:"amount" binding:"required,gt=0" example:"42.5"`
+	Currency  string         `json:"currency" binding:"required,len=3" example:"EUR"`
+	Category  string         `json:"category" binding:"required" example:"restaurant"`
+	Date      string         `json:"date" example:"2025-01-02"`
+	PayerID   uint           `json:"payerId"`
+	Note      string         `json:"note"`
+	SplitType string         `json:"splitType" example:"equal"`
+	Splits    []ExpenseSplit `json:"splits" gorm:"foreignKey:ExpenseID"`
+}
+
+// ExpenseSplit est la part d'une dépense due par un participant.
+// Shares n'est utilisé qu'en mode "shares", Amount est fourni en mode "exact" et calculé sinon.
+type ExpenseSplit struct {
+	ID        uint    `json:"id" gorm:"primaryKey"`
+	ExpenseID uint    `json:"-" gorm:"index;not null"`
+	UserID    uint    `json:"userId"`
+	Shares    float64 `json:"shares,omitempty"`
+	Amount    float64 `json:"amount"`
+}
+
+// Settlement est un remboursement effectué entre deux participants d'un voyage
+type Settlement struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	TripID     uint      `json:"tripId" gorm:"index;not null"`
+	FromUserID uint      `json:"fromUserId" binding:"required"`
+	ToUserID   uint      `json:"toUserId" binding:"required"`
+	Amount     float64   `json:"amount" binding:"required,gt=0" example:"25"`
+	Currency   string    `json:"currency" binding:"required,len=3" example:"EUR"`
+	Date       string    `json:"date" example:"2025-01-05"`
+	Note       string    `json:"note"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// TripBalances est l'état des comptes d'un voyage pour une devise donnée
+type TripBalances struct {
+	Currency  string        `json:"currency"`
+	Balances  []UserBalance `json:"balances"`
+	Transfers []Transfer    `json:"transfers"`
+}
+
+// UserBalance est la situation d'un participant : positif s'il doit recevoir de l'argent
+type UserBalance struct {
+	UserID uint    `json:"userId"`
+	Name   string  `json:"name"`
+	Paid   float64 `json:"paid"`
+	Owed   float64 `json:"owed"`
+	Net    float64 `json:"net"`
+}
+
+// Transfer est un virement à effectuer pour solder les comptes
+type Transfer struct {
+	FromUserID uint    `json:"fromUserId"`
+	ToUserID   uint    `json:"toUserId"`
+	Amount     float64 `json:"amount"`
+}
+
+// Budget représente le budget prévisionnel d'un voyage, découpé par catégorie
+type Budget struct {
+	ID         uint             `json:"id" gorm:"primaryKey"`
+	TripID     uint             `json:"tripId" gorm:"uniqueIndex;not null"`
+	Currency   string           `json:"currency" binding:"required,len=3" example:"EUR"`
+	Categories []BudgetCategory `json:"categories" gorm:"foreignKey:BudgetID"`
+}
+
+// BudgetCategory est le plafond prévu pour une catégorie de dépenses
+type BudgetCategory struct {
+	ID       uint    `json:"id" gorm:"primaryKey"`
+	BudgetID uint    `json:"-" gorm:"index;not null"`
+	Category string  `json:"category" example:"restaurant"`
+	Planned  float64 `json:"planned" example:"300"`
+}
+
+// BudgetSummary compare les dépenses réelles au budget prévu
+type BudgetSummary struct {
+	Currency        string                  `json:"currency"`
+	Planned         float64                 `json:"planned"`
+	Spent           float64                 `json:"spent"`
+	Remaining       float64                 `json:"remaining"`
+	Overrun         bool                    `json:"overrun"`
+	Categories      []BudgetCategorySummary `json:"categories"`
+	OtherCurrencies map[string]float64      `json:"otherCurrencies,omitempty"`
+}
+
+// BudgetCategorySummary est le bilan d'une catégorie de dépenses
+type BudgetCategorySummary struct {
+	Category  string  `json:"category"`
+	Planned   float64 `json:"planned"`
+	Spent     float64 `json:"spent"`
+	Remaining float64 `json:"remaining"`
+	Overrun   bool    `json:"overrun"`
+}
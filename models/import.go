@@ -0,0 +1,29 @@
+package models
+
+// Statuts d'une ligne ou d'un événement importé
+const (
+	ImportCreated   = "created"
+	ImportDuplicate = "duplicate"
+	ImportInvalid   = "invalid"
+	// Ligne valide mais non créée, car l'import tout ou rien a échoué
+	ImportValid = "valid"
+)
+
+// ImportRow est le résultat de l'import d'une ligne ou d'un événement du fichier.
+// En simulation (dryRun), Trip est le voyage qui serait créé, sans ID.
+type ImportRow struct {
+	Row         int    `json:"row"`
+	Status      string `json:"status" example:"created"`
+	Trip        *Trip  `json:"trip,omitempty"`
+	DuplicateOf uint   `json:"duplicateOf,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// ImportReport est le compte rendu d'un import de voyages
+type ImportReport struct {
+	DryRun     bool        `json:"dryRun"`
+	Created    int         `json:"created"`
+	Duplicates int         `json:"duplicates"`
+	Invalid    int         `json:"invalid"`
+	Rows       []ImportRow `json:"rows"`
+}
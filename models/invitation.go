@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Statuts d'une invitation
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationDeclined = "declined"
+	InvitationRevoked  = "revoked"
+)
+
+// Invitation est une proposition de rejoindre un voyage envoyée à une adresse email
+type Invitation struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	TripID      uint       `json:"tripId" gorm:"index;not null"`
+	Email       string     `json:"email" gorm:"index;not null"`
+	Role        string     `json:"role" example:"viewer"`
+	InvitedByID uint       `json:"invitedById"`
+	Status      string     `json:"status" example:"pending"`
+	ExpiresAt   time.Time  `json:"expiresAt"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	RespondedAt *time.Time `json:"respondedAt,omitempty"`
+	Trip        *Trip      `json:"trip,omitempty" gorm:"foreignKey:TripID"`
+}
@@ -0,0 +1,31 @@
+package models
+
+// ItineraryDay représente une journée du programme d'un voyage
+type ItineraryDay struct {
+	ID       uint   `json:"id" gorm:"primaryKey"`
+	TripID   uint   `json:"tripId" gorm:"index;not null"`
+	Date     string `json:"date" binding:"required" example:"2025-01-02"`
+	Position int    `json:"position"`
+	Title    string `json:"title"`
+	Notes    string `json:"notes"`
+	Stops    []Stop `json:"stops,omitempty" gorm:"foreignKey:DayID"`
+}
+
+// Stop représente une étape d'une journée (visite, restaurant, transport...)
+type Stop struct {
+	ID        uint    `json:"id" gorm:"primaryKey"`
+	DayID     uint    `json:"dayId" gorm:"index;not null"`
+	Position  int     `json:"position"`
+	Name      string  `json:"name" binding:"required"`
+	StartTime string  `json:"startTime" example:"09:30"`
+	EndTime   string  `json:"endTime" example:"11:00"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Notes     string  `json:"notes"`
+}
+
+// TripDetail est un voyage accompagné de son programme jour par jour
+type TripDetail struct {
+	Trip
+	Days []ItineraryDay `json:"days"`
+}
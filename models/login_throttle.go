@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// LoginThrottle compte les échecs de connexion récents d'un compte (adresse email saisie,
+// que le compte existe ou non) ou d'une adresse IP
+type LoginThrottle struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	Key           string     `json:"key" gorm:"uniqueIndex;not null" example:"account:alice@example.com"`
+	Failures      int        `json:"failures"`
+	LastFailureAt time.Time  `json:"lastFailureAt"`
+	LockedUntil   *time.Time `json:"lockedUntil,omitempty"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// OIDCLogin est une connexion en cours auprès d'un fournisseur d'identité, entre la redirection
+// vers le fournisseur et le retour. Elle n'est utilisable qu'une fois, et seulement par le
+// navigateur qui l'a commencée : BrowserHash est l'empreinte de l'identifiant placé dans son cookie.
+type OIDCLogin struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	Provider     string    `json:"provider" gorm:"not null"`
+	StateHash    string    `json:"-" gorm:"uniqueIndex;not null"`
+	BrowserHash  string    `json:"-" gorm:"not null;default:''"`
+	Nonce        string    `json:"-" gorm:"not null"`
+	CodeVerifier string    `json:"-" gorm:"not null"`
+	RedirectURL  string    `json:"-"`
+	ExpiresAt    time.Time `json:"expiresAt" gorm:"index"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+func (OIDCLogin) TableName() string {
+	return "oidc_logins"
+}
+
+// UserIdentity relie un compte à son identité chez un fournisseur OpenID Connect
+type UserIdentity struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	UserID      uint      `json:"userId" gorm:"index;not null"`
+	Provider    string    `json:"provider" gorm:"uniqueIndex:idx_identity_subject;not null"`
+	Subject     string    `json:"subject" gorm:"uniqueIndex:idx_identity_subject;not null"`
+	Email       string    `json:"email"`
+	LastLoginAt time.Time `json:"lastLoginAt"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// PasswordReset est une demande de réinitialisation du mot de passe. Le jeton envoyé par
+// email n'est conservé que sous forme d'empreinte et ne peut servir qu'une fois.
+type PasswordReset struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"userId" gorm:"index;not null"`
+	TokenHash string     `json:"-" gorm:"uniqueIndex;not null"`
+	ExpiresAt time.Time  `json:"expiresAt"`
+	UsedAt    *time.Time `json:"usedAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Session est une connexion d'un utilisateur depuis un appareil. Elle regroupe les jetons
+// de rafraîchissement successifs de cette connexion ; la révoquer déconnecte l'appareil.
+type Session struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"userId" gorm:"index;not null"`
+	UserAgent  string     `json:"userAgent" example:"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X)"`
+	IP         string     `json:"ip" example:"203.0.113.7"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastSeenAt time.Time  `json:"lastSeenAt"`
+	ExpiresAt  time.Time  `json:"expiresAt"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	Current    bool       `json:"current" gorm:"-"`
+}
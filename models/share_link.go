@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// ShareLink est un lien public en lecture seule vers un voyage
+type ShareLink struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	TripID       uint       `json:"tripId" gorm:"index;not null"`
+	TokenHash    string     `json:"-" gorm:"uniqueIndex;not null"`
+	CreatedByID  uint       `json:"createdById"`
+	IncludeNotes bool       `json:"includeNotes"`
+	IncludeOwner bool       `json:"includeOwner"`
+	ExpiresAt    *time.Time `json:"expiresAt"`
+	RevokedAt    *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	Token        string     `json:"token,omitempty" gorm:"-"`
+	URL          string     `json:"url,omitempty" gorm:"-"`
+}
+
+// SharedTrip est la vue publique d'un voyage, sans données personnelles sauf accord du propriétaire
+type SharedTrip struct {
+	Title       string      `json:"title"`
+	Description string      `json:"description"`
+	Location    string      `json:"location"`
+	StartDate   string      `json:"startDate"`
+	EndDate     string      `json:"endDate"`
+	Longitude   float64     `json:"longitude"`
+	Latitude    float64     `json:"latitude"`
+	Notes       string      `json:"notes,omitempty"`
+	UserID      uint        `json:"userId,omitempty"`
+	OwnerName   string      `json:"ownerName,omitempty"`
+	Days        []SharedDay `json:"days"`
+}
+
+// SharedDay est la vue publique d'une journée du programme
+type SharedDay struct {
+	Date  string       `json:"date"`
+	Title string       `json:"title"`
+	Notes string       `json:"notes,omitempty"`
+	Stops []SharedStop `json:"stops"`
+}
+
+// SharedStop est la vue publique d'une étape
+type SharedStop struct {
+	Name      string  `json:"name"`
+	StartTime string  `json:"startTime"`
+	EndTime   string  `json:"endTime"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Notes     string  `json:"notes,omitempty"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// RefreshToken est un jeton de rafraîchissement, conservé sous forme d'empreinte. Chaque
+// utilisation le remplace par un nouveau jeton de la même session (rotation) ; réutiliser
+// un jeton déjà remplacé révoque toute la session.
+type RefreshToken struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"userId" gorm:"index;not null"`
+	SessionID uint       `json:"sessionId" gorm:"index;not null"`
+	TokenHash string     `json:"-" gorm:"uniqueIndex;not null"`
+	ExpiresAt time.Time  `json:"expiresAt"`
+	UsedAt    *time.Time `json:"usedAt,omitempty"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// RevokedToken est un jeton d'accès révoqué avant son expiration (déconnexion).
+// Il est conservé jusqu'à la date d'expiration du jeton.
+type RevokedToken struct {
+	JTI       string    `json:"jti" gorm:"primaryKey"`
+	ExpiresAt time.Time `json:"expiresAt" gorm:"index"`
+}
+
+// TokenPair est la réponse d'authentification : un jeton d'accès de courte durée et le
+// jeton de rafraîchissement permettant d'en obtenir un nouveau
+type TokenPair struct {
+	Token        string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	RefreshToken string `json:"refreshToken" example:"q3Jx0v8bR2m..."`
+	ExpiresIn    int    `json:"expiresIn" example:"900"`
+}
@@ -7,8 +7,27 @@ type Trip struct {
 	Location    string  `json:"location"`
 	StartDate   string  `json:"startDate"`
 	EndDate     string  `json:"endDate"`
-	Longitude   float64 `json:"longitude"`
-	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude" gorm:"index:idx_trip_coordinates,priority:2"`
+	Latitude    float64 `json:"latitude" gorm:"index:idx_trip_coordinates,priority:1"`
 	Notes       string  `json:"notes"`
 	UserID      uint    `json:"userId"`
 }
+
+// BulkTripResult est le résultat d'une opération groupée pour un voyage
+type BulkTripResult struct {
+	ID     uint   `json:"id"`
+	Status string `json:"status" example:"updated"`
+}
+
+// TripSearchResult est un voyage trouvé par la recherche plein texte
+type TripSearchResult struct {
+	Trip
+	Snippet string  `json:"snippet" example:"Week-end à <mark>Paris</mark> en famille"`
+	Score   float64 `json:"score"`
+}
+
+// TripDistance est un voyage accompagné de sa distance au point de recherche
+type TripDistance struct {
+	Trip
+	DistanceKm float64 `json:"distanceKm" example:"12.4"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Rôles d'un membre sur un voyage, du plus au moins privilégié
+const (
+	RoleOwner  = "owner"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+// TripMember associe un utilisateur à un voyage partagé avec un rôle
+type TripMember struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	TripID    uint      `json:"tripId" gorm:"uniqueIndex:idx_trip_member;not null"`
+	UserID    uint      `json:"userId" gorm:"uniqueIndex:idx_trip_member;not null"`
+	Role      string    `json:"role" example:"editor"`
+	CreatedAt time.Time `json:"createdAt"`
+	User      *User     `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+// RoleRank retourne le niveau de privilège d'un rôle (0 pour un rôle inconnu)
+func RoleRank(role string) int {
+	switch role {
+	case RoleOwner:
+		return 3
+	case RoleEditor:
+		return 2
+	case RoleViewer:
+		return 1
+	}
+	return 0
+}
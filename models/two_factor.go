@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// RecoveryCode est un code de secours à usage unique, utilisable à la place d'un code TOTP
+// si l'application d'authentification n'est plus disponible. Seule son empreinte est conservée.
+type RecoveryCode struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"userId" gorm:"index;not null"`
+	CodeHash  string     `json:"-" gorm:"index;not null"`
+	UsedAt    *time.Time `json:"usedAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// TwoFactorSetup contient le secret à enregistrer dans l'application d'authentification
+type TwoFactorSetup struct {
+	Secret     string `json:"secret" example:"JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+	OTPAuthURI string `json:"otpauthUri" example:"otpauth://totp/TravelMate:jean@example.com?secret=JBSWY3DPEHPK3PXP&issuer=TravelMate"`
+	QRCode     string `json:"qrCode" example:"data:image/png;base64,iVBORw0KGgo..."`
+}
+
+// RecoveryCodes est la liste des codes de secours, affichée une seule fois
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recoveryCodes" example:"k3j9d-x8q2m,p7w4n-c5v1b"`
+}
+
+// TwoFactorChallenge est la réponse de connexion d'un compte protégé par la double
+// authentification : le jeton permet de terminer la connexion avec un code
+type TwoFactorChallenge struct {
+	TwoFactorRequired bool   `json:"twoFactorRequired" example:"true"`
+	ChallengeToken    string `json:"challengeToken" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	ExpiresIn         int    `json:"expiresIn" example:"300"`
+}
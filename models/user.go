@@ -1,11 +1,17 @@
 package models
 
 type User struct {
-	ID       uint   `gorm:"primaryKey" json:"id"`
-	Name     string `json:"name" validate:"required"`
-	Email    string `gorm:"uniqueIndex" json:"email" validate:"required,email"`
-	Password string `gorm:"type:text;not null" json:"-"` 
-	IsAdmin  bool   `json:"isAdmin"`
+	ID            uint   `gorm:"primaryKey" json:"id"`
+	Name          string `json:"name" validate:"required"`
+	Email         string `gorm:"uniqueIndex" json:"email" validate:"required,email"`
+	Password      string `gorm:"type:text;not null" json:"-"`
+	IsAdmin       bool   `json:"isAdmin"`
+	EmailVerified bool   `json:"emailVerified"`
+	// Double authentification : le secret est enregistré dès l'activation et n'est utilisé
+	// qu'une fois confirmé (TwoFactorEnabled). TOTPLastStep empêche de rejouer un code.
+	TwoFactorEnabled bool   `json:"twoFactorEnabled"`
+	TOTPSecret       string `json:"-"`
+	TOTPLastStep     int64  `json:"-"`
 }
 
 type Register struct {
@@ -16,5 +22,5 @@ type Register struct {
 
 type RegisterResponse struct {
     Message string `json:"message" example:"Inscription réussie"`
-    Token   string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+    TokenPair
 }
@@ -17,38 +17,135 @@ func SetupRoutes(r *gin.Engine) {
     r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
     
     // Auth
+    r.GET("/.well-known/jwks.json", controllers.GetJWKS)
     r.POST("/login", controllers.Login)
     r.POST("/register", controllers.Register)
+    r.POST("/auth/refresh", controllers.RefreshToken)
+    r.POST("/auth/forgot-password", controllers.ForgotPassword)
+    r.POST("/auth/reset-password", controllers.ResetPassword)
+    r.POST("/auth/verify-email", controllers.VerifyEmail)
+    r.POST("/auth/2fa/verify", controllers.VerifyTwoFactorLogin)
+    r.GET("/auth/oidc/:provider/login", controllers.OIDCLogin)
+    r.GET("/auth/oidc/:provider/callback", controllers.OIDCCallback)
+
+    // Voyages partagés par lien public
+    r.GET("/shared/:token", middleware.RequestLogger(), controllers.GetSharedTrip)
+
+    // Flux iCalendar, authentifié par le jeton de l'adresse
+    r.GET("/calendar/:token", middleware.RequestLogger(), controllers.GetCalendarFeed)
     
     // Utilisation du middlewate sur l'ensemble des routes
     protected := r.Group("/")
 	protected.Use(middleware.AuthMiddleware())
     protected.Use(middleware.RequestLogger())
-    protected.GET("/me", controllers.GetMe)
+
+    // Compte : réservé aux connexions par mot de passe, refusé aux jetons d'API
+    account := protected.Group("/", middleware.SessionOnly())
+    account.POST("/auth/logout", controllers.Logout)
+    account.POST("/auth/logout-all", controllers.LogoutAll)
+    account.POST("/auth/resend-verification", controllers.ResendEmailVerification)
+    account.GET("/me/sessions", controllers.GetMySessions)
+    account.DELETE("/me/sessions/:session", controllers.DeleteMySession)
+    account.POST("/me/2fa/setup", controllers.SetupTwoFactor)
+    account.POST("/me/2fa/confirm", controllers.ConfirmTwoFactor)
+    account.POST("/me/2fa/recovery-codes", controllers.RegenerateRecoveryCodes)
+    account.DELETE("/me/2fa", controllers.DisableTwoFactor)
+    account.GET("/me/tokens", controllers.GetAPITokens)
+    account.POST("/me/tokens", controllers.CreateAPIToken)
+    account.DELETE("/me/tokens/:token", controllers.DeleteAPIToken)
+    account.POST("/me/calendar-feed", controllers.CreateCalendarFeed)
+    account.DELETE("/me/calendar-feed", controllers.DeleteCalendarFeed)
+    account.PUT("/users/:id", controllers.UpdateUser)
 
 	// Users
-	protected.GET("/users", controllers.GetUsers)
-	protected.GET("/user", controllers.GetUsersByEmail)
-    protected.PUT("/users/:id", controllers.UpdateUser)
-    protected.GET("/users/:id/trips", controllers.GetTripsByUserID)
+    users := protected.Group("/", middleware.RequireReadScope(middleware.ScopeUsersRead))
+    users.GET("/me", controllers.GetMe)
+	users.GET("/users", controllers.GetUsers)
+	users.GET("/user", controllers.GetUsersByEmail)
 
     // Trips
-    tripGroup := protected.Group("/trips")
+    trips := protected.Group("/", middleware.RequireScope(middleware.ScopeTripsRead, middleware.ScopeTripsWrite))
+    trips.GET("/users/:id/trips", controllers.GetTripsByUserID)
+    trips.GET("/trips.geojson", controllers.GetTripsGeoJSON)
+    tripGroup := trips.Group("/trips")
     {
         tripGroup.GET("", controllers.GetTrips)
 		tripGroup.GET("/:id", controllers.GetTripByID)
-		tripGroup.POST("", controllers.CreateTrip)
+		tripGroup.POST("", middleware.RequireVerifiedEmail(middleware.ActionCreate), controllers.CreateTrip)
 		tripGroup.PUT("/:id", controllers.UpdateTrip)
 		tripGroup.PUT("/", controllers.UpdateMultipleTrips)
 		tripGroup.DELETE("/:id", controllers.DeleteTrip)
 		tripGroup.DELETE("", controllers.DeleteMultipleTrips)
 		tripGroup.GET("/search", controllers.SearchTrips)
+		tripGroup.GET("/near", controllers.GetTripsNear)
+		tripGroup.GET("/within", controllers.GetTripsWithin)
+		tripGroup.POST("/import", middleware.RequireVerifiedEmail(middleware.ActionCreate), controllers.ImportTripsSpreadsheet)
+		tripGroup.POST("/import/ics", middleware.RequireVerifiedEmail(middleware.ActionCreate), controllers.ImportTripsICS)
+		tripGroup.GET("/export", controllers.ExportTripsSpreadsheet)
+		tripGroup.GET("/export.gpx", controllers.ExportTripsGPX)
+		tripGroup.GET("/export.kml", controllers.ExportTripsKML)
+
+		// Programme jour par jour
+		tripGroup.GET("/:id/calendar.ics", controllers.ExportTripCalendar)
+		tripGroup.GET("/:id/export.gpx", controllers.ExportTripGPX)
+		tripGroup.GET("/:id/export.kml", controllers.ExportTripKML)
+		tripGroup.GET("/:id/itinerary.pdf", controllers.ExportItineraryPDF)
+
+		tripGroup.GET("/:id/days", controllers.GetItinerary)
+		tripGroup.POST("/:id/days", controllers.CreateItineraryDay)
+		tripGroup.GET("/:id/days/:day", controllers.GetItineraryDay)
+		tripGroup.PUT("/:id/days/:day", controllers.UpdateItineraryDay)
+		tripGroup.DELETE("/:id/days/:day", controllers.DeleteItineraryDay)
+		tripGroup.GET("/:id/days/:day/stops", controllers.GetStops)
+		tripGroup.POST("/:id/days/:day/stops", controllers.CreateStop)
+		tripGroup.PUT("/:id/days/:day/stops/:stop", controllers.UpdateStop)
+		tripGroup.DELETE("/:id/days/:day/stops/:stop", controllers.DeleteStop)
+
+		// Dépenses et budget
+		tripGroup.GET("/:id/expenses", controllers.GetExpenses)
+		tripGroup.POST("/:id/expenses", controllers.CreateExpense)
+		tripGroup.GET("/:id/expenses/:expense", controllers.GetExpense)
+		tripGroup.PUT("/:id/expenses/:expense", controllers.UpdateExpense)
+		tripGroup.DELETE("/:id/expenses/:expense", controllers.DeleteExpense)
+		tripGroup.GET("/:id/budget", controllers.GetBudget)
+		tripGroup.PUT("/:id/budget", controllers.SetBudget)
+		tripGroup.GET("/:id/balances", controllers.GetBalances)
+		tripGroup.GET("/:id/settlements", controllers.GetSettlements)
+		tripGroup.POST("/:id/settlements", controllers.CreateSettlement)
+		tripGroup.DELETE("/:id/settlements/:settlement", controllers.DeleteSettlement)
+
+		// Membres
+		tripGroup.GET("/:id/members", controllers.GetTripMembers)
+		tripGroup.POST("/:id/members", middleware.RequireVerifiedEmail(middleware.ActionShare), controllers.AddTripMember)
+		tripGroup.PUT("/:id/members/:user", controllers.UpdateTripMember)
+		tripGroup.DELETE("/:id/members/:user", controllers.RemoveTripMember)
+
+		// Invitations
+		tripGroup.GET("/:id/invitations", controllers.GetTripInvitations)
+		tripGroup.POST("/:id/invitations", middleware.RequireVerifiedEmail(middleware.ActionShare), controllers.CreateInvitation)
+		tripGroup.DELETE("/:id/invitations/:invitation", controllers.RevokeInvitation)
+
+		// Liens de partage
+		tripGroup.GET("/:id/share-links", controllers.GetShareLinks)
+		tripGroup.POST("/:id/share-links", middleware.RequireVerifiedEmail(middleware.ActionShare), controllers.CreateShareLink)
+		tripGroup.DELETE("/:id/share-links/:link", controllers.RevokeShareLink)
     }
 
+    // Invitations reçues
+    trips.GET("/invitations", controllers.GetMyInvitations)
+    trips.POST("/invitations/accept", middleware.RequireVerifiedEmail(middleware.ActionShare), controllers.AcceptInvitationToken)
+    trips.POST("/invitations/:invitation/accept", middleware.RequireVerifiedEmail(middleware.ActionShare), controllers.AcceptInvitation)
+    trips.POST("/invitations/:invitation/decline", controllers.DeclineInvitation)
+
     // Admin
     admin := protected.Group("/admin")
     admin.Use(middleware.IsAdmin())
     {
         admin.POST("/reset", controllers.ResetDatabase)
+        admin.GET("/users/:id/sessions", controllers.GetUserSessions)
+        admin.DELETE("/users/:id/sessions", controllers.DeleteUserSessions)
+        admin.DELETE("/users/:id/sessions/:session", controllers.DeleteUserSession)
+        admin.POST("/users/:id/unlock", controllers.UnlockUser)
+        admin.GET("/audit-events", controllers.GetAuditEvents)
     }
 }
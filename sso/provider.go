@@ -0,0 +1,165 @@
+package sso
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"travelmate-api/logger"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// ErrUnknownProvider signale un fournisseur d'identité absent de OIDC_PROVIDERS
+var ErrUnknownProvider = errors.New("unknown identity provider")
+
+// Délai maximal d'un appel au fournisseur d'identité (découverte, échange du code)
+const requestTimeout = 10 * time.Second
+
+// Provider est un fournisseur d'identité OpenID Connect. Sa configuration est découverte
+// (/.well-known/openid-configuration) à la première connexion, puis conservée.
+type Provider struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL est l'adresse de retour déclarée auprès du fournisseur ; si elle est vide,
+	// la route de retour de l'API est utilisée
+	RedirectURL string
+	Scopes      []string
+
+	mu       sync.Mutex
+	provider *oidc.Provider
+}
+
+var providers = map[string]*Provider{}
+
+// InitProviders lit les fournisseurs d'identité configurés : OIDC_PROVIDERS liste leurs noms
+// (par exemple "entreprise,google") et chacun est configuré par OIDC_<NOM>_ISSUER,
+// OIDC_<NOM>_CLIENT_ID, OIDC_<NOM>_CLIENT_SECRET, et éventuellement OIDC_<NOM>_REDIRECT_URL
+// et OIDC_<NOM>_SCOPES (séparés par des espaces, "openid email profile" par défaut)
+func InitProviders() {
+	providers = map[string]*Provider{}
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		provider := &Provider{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  os.Getenv(prefix + "REDIRECT_URL"),
+			Scopes:       strings.Fields(os.Getenv(prefix + "SCOPES")),
+		}
+		if provider.Issuer == "" || provider.ClientID == "" {
+			logger.ErrorLogger.Printf("Fournisseur d'identité %s ignoré : %sISSUER et %sCLIENT_ID sont requis", name, prefix, prefix)
+			continue
+		}
+		if len(provider.Scopes) == 0 {
+			provider.Scopes = []string{oidc.ScopeOpenID, "email", "profile"}
+		}
+		providers[name] = provider
+	}
+}
+
+// Lookup retourne le fournisseur d'identité configuré sous ce nom
+func Lookup(name string) (*Provider, error) {
+	provider, ok := providers[strings.ToLower(name)]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	return provider, nil
+}
+
+// discover retourne la configuration du fournisseur, découverte au premier appel. Un échec
+// n'est pas conservé : la découverte est retentée à la connexion suivante.
+func (p *Provider) discover(ctx context.Context) (*oidc.Provider, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.provider != nil {
+		return p.provider, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	provider, err := oidc.NewProvider(ctx, p.Issuer)
+	if err != nil {
+		return nil, err
+	}
+	p.provider = provider
+	return provider, nil
+}
+
+// oauth2Config retourne la configuration OAuth2 du fournisseur pour l'adresse de retour donnée
+func (p *Provider) oauth2Config(provider *oidc.Provider, redirectURL string) *oauth2.Config {
+	if p.RedirectURL != "" {
+		redirectURL = p.RedirectURL
+	}
+	return &oauth2.Config{
+		ClientID:     p.ClientID,
+		ClientSecret: p.ClientSecret,
+		Endpoint:     provider.Endpoint(),
+		RedirectURL:  redirectURL,
+		Scopes:       p.Scopes,
+	}
+}
+
+// AuthCodeURL retourne l'adresse de connexion du fournisseur (code d'autorisation avec PKCE S256)
+func (p *Provider) AuthCodeURL(ctx context.Context, redirectURL, state, nonce, verifier string) (string, error) {
+	provider, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	config := p.oauth2Config(provider, redirectURL)
+	return config.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)), nil
+}
+
+// Claims sont les informations d'identité retenues du jeton d'identité
+type Claims struct {
+	Subject       string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+}
+
+// Exchange échange le code d'autorisation contre les jetons du fournisseur, vérifie le jeton
+// d'identité (signature, émetteur, audience, expiration, nonce) et retourne ses informations
+func (p *Provider) Exchange(ctx context.Context, redirectURL, code, nonce, verifier string) (*Claims, error) {
+	provider, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	token, err := p.oauth2Config(provider, redirectURL).Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, err
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("id_token missing from token response")
+	}
+
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: p.ClientID}).Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+	if idToken.Nonce != nonce {
+		return nil, errors.New("id_token nonce mismatch")
+	}
+
+	var claims Claims
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
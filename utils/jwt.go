@@ -3,50 +3,60 @@ package utils
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 )
 
-var jwtKey = []byte(getJWTSecret())
-
 func HashPassword(password string) (string, error) {
     bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
     return string(bytes), err
 }
 
-func getJWTSecret() []byte {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		secret = "my_very_secret_key" 
+// AccessTokenTTL retourne la durée de validité d'un jeton d'accès (ACCESS_TOKEN_TTL_MINUTES, 15 minutes par défaut)
+func AccessTokenTTL() time.Duration {
+	if minutes, err := strconv.Atoi(os.Getenv("ACCESS_TOKEN_TTL_MINUTES")); err == nil && minutes > 0 {
+		return time.Duration(minutes) * time.Minute
 	}
-	return []byte(secret)
+	return 15 * time.Minute
 }
 
-func GenerateJWT(userID uint, userName string, isAdmin bool) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id":  userID,
-		"user_name":  userName,
-		"is_admin": isAdmin,
-		"exp":      time.Now().Add(time.Hour * 24).Unix(),
-	})
+// RefreshTokenTTL retourne la durée de validité d'un jeton de rafraîchissement (REFRESH_TOKEN_TTL_DAYS, 30 jours par défaut)
+func RefreshTokenTTL() time.Duration {
+	if days, err := strconv.Atoi(os.Getenv("REFRESH_TOKEN_TTL_DAYS")); err == nil && days > 0 {
+		return time.Duration(days) * 24 * time.Hour
+	}
+	return 30 * 24 * time.Hour
+}
 
-	tokenString, err := token.SignedString(getJWTSecret())
+// GenerateJWT signe un jeton d'accès de courte durée. Son identifiant (jti) permet de le révoquer
+// et sid désigne la session (connexion) dont il est issu.
+func GenerateJWT(userID uint, userName string, isAdmin bool, sessionID uint) (string, error) {
+	jti, err := GenerateRandomToken()
 	if err != nil {
 		return "", err
 	}
 
-	return tokenString, nil
+	now := time.Now()
+	return signingKeys.sign(jwt.MapClaims{
+		"typ":       "access",
+		"jti":       jti,
+		"sid":       sessionID,
+		"user_id":   userID,
+		"user_name": userName,
+		"is_admin":  isAdmin,
+		"iat":       now.Unix(),
+		"exp":       now.Add(AccessTokenTTL()).Unix(),
+	})
 }
 
+// ParseToken vérifie la signature et l'expiration d'un jeton signé par l'API
 func ParseToken(tokenString string) (jwt.MapClaims, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return getJWTSecret(), nil
-	})
+	token, err := jwt.Parse(tokenString, signingKeys.verificationKey, jwt.WithValidMethods([]string{
+		jwt.SigningMethodEdDSA.Alg(), jwt.SigningMethodRS256.Alg(), jwt.SigningMethodHS256.Alg(),
+	}))
 
 	if err != nil || !token.Valid {
 		return nil, err
@@ -59,3 +69,82 @@ func ParseToken(tokenString string) (jwt.MapClaims, error) {
 
 	return claims, nil
 }
+
+// ParseAccessToken vérifie un jeton d'accès ; les autres jetons signés (invitations...) sont refusés
+func ParseAccessToken(tokenString string) (jwt.MapClaims, error) {
+	claims, err := ParseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims["typ"] != "access" {
+		return nil, fmt.Errorf("unexpected token type: %v", claims["typ"])
+	}
+	if _, ok := claims["jti"].(string); !ok {
+		return nil, fmt.Errorf("missing token id")
+	}
+	return claims, nil
+}
+
+// GenerateChallengeToken signe le jeton remis après la vérification du mot de passe d'un
+// compte protégé par la double authentification ; il ne donne accès qu'à la seconde étape
+func GenerateChallengeToken(userID uint, expiresAt time.Time) (string, error) {
+	jti, err := GenerateRandomToken()
+	if err != nil {
+		return "", err
+	}
+
+	return signingKeys.sign(jwt.MapClaims{
+		"typ":     "2fa_challenge",
+		"jti":     jti,
+		"user_id": userID,
+		"exp":     expiresAt.Unix(),
+	})
+}
+
+// ParseChallengeToken vérifie un jeton de double authentification et retourne l'utilisateur
+// qu'il désigne et son identifiant (jti)
+func ParseChallengeToken(tokenString string) (uint, string, error) {
+	claims, err := ParseToken(tokenString)
+	if err != nil {
+		return 0, "", err
+	}
+	if claims["typ"] != "2fa_challenge" {
+		return 0, "", fmt.Errorf("unexpected token type: %v", claims["typ"])
+	}
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return 0, "", fmt.Errorf("missing user id")
+	}
+	jti, ok := claims["jti"].(string)
+	if !ok {
+		return 0, "", fmt.Errorf("missing token id")
+	}
+	return uint(userID), jti, nil
+}
+
+// GenerateInvitationToken signe un jeton d'invitation à un voyage, valable jusqu'à expiresAt
+func GenerateInvitationToken(invitationID uint, email string, expiresAt time.Time) (string, error) {
+	return signingKeys.sign(jwt.MapClaims{
+		"typ":    "invitation",
+		"inv_id": invitationID,
+		"email":  email,
+		"exp":    expiresAt.Unix(),
+	})
+}
+
+// ParseInvitationToken vérifie un jeton d'invitation et retourne l'invitation et l'email qu'il désigne
+func ParseInvitationToken(tokenString string) (uint, string, error) {
+	claims, err := ParseToken(tokenString)
+	if err != nil {
+		return 0, "", err
+	}
+	if claims["typ"] != "invitation" {
+		return 0, "", fmt.Errorf("unexpected token type: %v", claims["typ"])
+	}
+	invitationID, ok := claims["inv_id"].(float64)
+	if !ok {
+		return 0, "", fmt.Errorf("missing invitation id")
+	}
+	email, _ := claims["email"].(string)
+	return uint(invitationID), email, nil
+}
@@ -0,0 +1,343 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"travelmate-api/logger"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Intervalle entre deux vérifications de la rotation des clés
+const keyRotationCheckInterval = time.Hour
+
+// Format de la date de création au début du nom des fichiers de clé
+const keyTimeFormat = "20060102T150405Z"
+
+// Anciennes valeurs par défaut de JWT_SECRET, refusées en production
+var defaultJWTSecrets = []string{"my_very_secret_key", "your_secret_key", "JWT_SECRET", "secret", "changeme"}
+
+// signingKey est une clé de signature des jetons, chargée depuis JWT_KEYS_DIR. Son identifiant
+// (kid) est le nom du fichier, qui commence par sa date de création en UTC
+// (20060102T150405Z-<suffixe aléatoire>.pem) : la date du fichier, modifiée par une copie ou
+// une restauration, avancerait ou retarderait la rotation.
+type signingKey struct {
+	ID        string
+	Method    jwt.SigningMethod
+	Private   crypto.Signer
+	CreatedAt time.Time
+	path      string
+}
+
+// keySet regroupe les clés de signature, de la plus ancienne à la plus récente : la plus
+// récente signe les nouveaux jetons, les précédentes vérifient encore les jetons qu'elles
+// ont signés pendant la durée de rétention
+type keySet struct {
+	mu          sync.RWMutex
+	keys        []*signingKey
+	legacy      []byte
+	legacyUntil time.Time
+}
+
+var signingKeys = &keySet{}
+
+// isProduction indique si l'API tourne en production (GIN_MODE=release)
+func isProduction() bool {
+	return os.Getenv("GIN_MODE") == "release"
+}
+
+func keysDir() string {
+	if dir := os.Getenv("JWT_KEYS_DIR"); dir != "" {
+		return dir
+	}
+	return "keys"
+}
+
+// keyRotationInterval retourne l'âge au-delà duquel une nouvelle clé est générée (JWT_KEY_ROTATION_DAYS,
+// 30 jours par défaut, 0 pour désactiver la rotation)
+func keyRotationInterval() time.Duration {
+	if days, err := strconv.Atoi(os.Getenv("JWT_KEY_ROTATION_DAYS")); err == nil && days >= 0 {
+		return time.Duration(days) * 24 * time.Hour
+	}
+	return 30 * 24 * time.Hour
+}
+
+// keyRetention retourne la durée pendant laquelle une clé remplacée vérifie encore les jetons
+// (JWT_KEY_RETENTION_DAYS, 14 jours par défaut). Elle doit dépasser la durée de vie du plus
+// long des jetons signés, les invitations (INVITATION_TTL_HOURS).
+func keyRetention() time.Duration {
+	if days, err := strconv.Atoi(os.Getenv("JWT_KEY_RETENTION_DAYS")); err == nil && days > 0 {
+		return time.Duration(days) * 24 * time.Hour
+	}
+	return 14 * 24 * time.Hour
+}
+
+// InitKeys charge les clés de signature des jetons depuis JWT_KEYS_DIR, en génère une si
+// nécessaire (JWT_SIGNING_ALG : EdDSA par défaut, ou RS256) et planifie leur rotation.
+//
+// Si JWT_SECRET est défini, les jetons HS256 émis avant le passage aux clés asymétriques
+// restent acceptés pendant la durée de rétention, comptée depuis la création de la plus
+// ancienne clé du répertoire : au-delà, ils ne peuvent plus être encore valides et le secret
+// est ignoré. En production, une valeur par défaut de JWT_SECRET est refusée.
+//
+// La rotation est propre à chaque instance : plusieurs instances de l'API doivent partager
+// le même JWT_KEYS_DIR (volume commun), sinon chacune génère ses propres clés et refuse les
+// jetons signés par les autres.
+func InitKeys() error {
+	secret := os.Getenv("JWT_SECRET")
+	if isProduction() && slices.Contains(defaultJWTSecrets, secret) {
+		return errors.New("JWT_SECRET utilise une valeur par défaut : définissez un secret ou supprimez la variable")
+	}
+
+	if err := signingKeys.load(keysDir()); err != nil {
+		return err
+	}
+	if err := signingKeys.rotate(); err != nil {
+		return err
+	}
+	if secret != "" {
+		signingKeys.acceptLegacy([]byte(secret))
+	}
+
+	go func() {
+		for range time.Tick(keyRotationCheckInterval) {
+			if err := signingKeys.rotate(); err != nil {
+				logger.ErrorLogger.Printf("Erreur lors de la rotation des clés de signature : %v", err)
+			}
+		}
+	}()
+	return nil
+}
+
+// acceptLegacy accepte les jetons HS256 signés avec le secret jusqu'à la fin de la rétention
+// de la plus ancienne clé asymétrique, créée au passage aux clés asymétriques
+func (s *keySet) acceptLegacy(secret []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	until := s.keys[0].CreatedAt.Add(keyRetention())
+	if time.Now().After(until) {
+		logger.InfoLogger.Printf("JWT_SECRET ignoré : les jetons HS256 ne sont plus acceptés depuis le %s", until.Format("02/01/2006"))
+		return
+	}
+	s.legacy, s.legacyUntil = secret, until
+	logger.InfoLogger.Printf("Jetons HS256 (JWT_SECRET) acceptés jusqu'au %s", until.Format("02/01/2006 à 15:04"))
+}
+
+// load lit les clés PEM (PKCS#8, ou PKCS#1 pour RSA) du répertoire
+func (s *keySet) load(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return err
+	}
+
+	keys := []*signingKey{}
+	for _, path := range paths {
+		key, err := readSigningKey(path)
+		if err != nil {
+			return fmt.Errorf("clé de signature %s : %w", path, err)
+		}
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b *signingKey) int { return a.CreatedAt.Compare(b.CreatedAt) })
+
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+	return nil
+}
+
+func readSigningKey(path string) (*signingKey, error) {
+	id := strings.TrimSuffix(filepath.Base(path), ".pem")
+	stamp, _, _ := strings.Cut(id, "-")
+	createdAt, err := time.Parse(keyTimeFormat, stamp)
+	if err != nil {
+		return nil, fmt.Errorf("le nom du fichier doit commencer par la date de création (%s)", keyTimeFormat)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("fichier PEM invalide")
+	}
+
+	var private any
+	switch block.Type {
+	case "PRIVATE KEY":
+		private, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		private, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("type de clé non pris en charge : %s", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &signingKey{
+		ID:        id,
+		CreatedAt: createdAt,
+		path:      path,
+	}
+	switch private := private.(type) {
+	case *rsa.PrivateKey:
+		if private.N.BitLen() < 2048 {
+			return nil, errors.New("une clé RSA doit faire au moins 2048 bits")
+		}
+		key.Method, key.Private = jwt.SigningMethodRS256, private
+	case ed25519.PrivateKey:
+		key.Method, key.Private = jwt.SigningMethodEdDSA, private
+	default:
+		return nil, errors.New("seules les clés RSA et Ed25519 sont prises en charge")
+	}
+	return key, nil
+}
+
+// generateSigningKey crée une clé selon JWT_SIGNING_ALG et l'enregistre dans le répertoire
+func generateSigningKey(dir string) (*signingKey, error) {
+	var private crypto.Signer
+	var err error
+	switch alg := os.Getenv("JWT_SIGNING_ALG"); alg {
+	case "", jwt.SigningMethodEdDSA.Alg():
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	case jwt.SigningMethodRS256.Alg():
+		private, err = rsa.GenerateKey(rand.Reader, 3072)
+	default:
+		return nil, fmt.Errorf("JWT_SIGNING_ALG non pris en charge : %s (EdDSA ou RS256)", alg)
+	}
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	// Le suffixe aléatoire évite la collision de deux clés créées dans la même seconde par des
+	// instances partageant le répertoire
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, time.Now().UTC().Format(keyTimeFormat)+"-"+hex.EncodeToString(suffix)+".pem")
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	if err := pem.Encode(file, &pem.Block{Type: "PRIVATE KEY", Bytes: der}); err != nil {
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+	return readSigningKey(path)
+}
+
+// rotate génère une nouvelle clé de signature lorsque la clé courante a dépassé l'intervalle
+// de rotation, et supprime les clés remplacées depuis plus longtemps que la durée de rétention
+func (s *keySet) rotate() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	interval := keyRotationInterval()
+	if len(s.keys) == 0 || (interval > 0 && time.Since(s.keys[len(s.keys)-1].CreatedAt) >= interval) {
+		key, err := generateSigningKey(keysDir())
+		if err != nil {
+			return err
+		}
+		s.keys = append(s.keys, key)
+		logger.InfoLogger.Printf("Nouvelle clé de signature des jetons : %s (%s)", key.ID, key.Method.Alg())
+	}
+
+	// Une clé est remplacée à la création de la suivante
+	kept := []*signingKey{}
+	for i, key := range s.keys {
+		if i < len(s.keys)-1 && time.Since(s.keys[i+1].CreatedAt) > keyRetention() {
+			if err := os.Remove(key.path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			logger.InfoLogger.Printf("Clé de signature des jetons retirée : %s", key.ID)
+			continue
+		}
+		kept = append(kept, key)
+	}
+	s.keys = kept
+	return nil
+}
+
+// sign signe les claims avec la clé courante, désignée par l'en-tête kid
+func (s *keySet) sign(claims jwt.MapClaims) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.keys) == 0 {
+		return "", errors.New("no signing key loaded")
+	}
+
+	key := s.keys[len(s.keys)-1]
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Private)
+}
+
+// verificationKey retourne la clé publique désignée par l'en-tête kid du jeton, ou le secret
+// JWT_SECRET pour les jetons HS256 émis avant le passage aux clés asymétriques, tant qu'ils
+// peuvent encore être valides
+func (s *keySet) verificationKey(token *jwt.Token) (any, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" && token.Method == jwt.SigningMethodHS256 && s.legacy != nil && time.Now().Before(s.legacyUntil) {
+		return s.legacy, nil
+	}
+	for _, key := range s.keys {
+		if key.ID == kid {
+			if token.Method != key.Method {
+				return nil, fmt.Errorf("unexpected signing method for key %s: %v", kid, token.Header["alg"])
+			}
+			return key.Private.Public(), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown signing key: %q", kid)
+}
+
+// JWKS retourne les clés publiques permettant de vérifier les jetons (clé courante et clés
+// remplacées encore en rétention)
+func JWKS() jose.JSONWebKeySet {
+	signingKeys.mu.RLock()
+	defer signingKeys.mu.RUnlock()
+
+	set := jose.JSONWebKeySet{Keys: []jose.JSONWebKey{}}
+	for _, key := range signingKeys.keys {
+		set.Keys = append(set.Keys, jose.JSONWebKey{
+			Key:       key.Private.Public(),
+			KeyID:     key.ID,
+			Algorithm: key.Method.Alg(),
+			Use:       "sig",
+		})
+	}
+	return set
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadSigningKeyCreatedAtFromName(t *testing.T) {
+	dir := t.TempDir()
+	key, err := generateSigningKey(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(key.CreatedAt) > time.Minute {
+		t.Fatalf("CreatedAt = %v, want now", key.CreatedAt)
+	}
+
+	// Une copie ou une restauration change la date du fichier, pas celle de la clé
+	old := filepath.Join(dir, "20250102T030405Z-0a1b2c3d.pem")
+	if err := os.Rename(key.path, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(old, time.Now(), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	key, err = readSigningKey(old)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC); !key.CreatedAt.Equal(want) || key.ID != "20250102T030405Z-0a1b2c3d" {
+		t.Errorf("readSigningKey() = %s, %v, want 20250102T030405Z-0a1b2c3d, %v", key.ID, key.CreatedAt, want)
+	}
+
+	// Les clés nommées avant l'ajout du suffixe aléatoire restent lisibles
+	legacy := filepath.Join(dir, "20250102T030405Z.pem")
+	if err := os.Rename(old, legacy); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readSigningKey(legacy); err != nil {
+		t.Errorf("readSigningKey(%s) error = %v", legacy, err)
+	}
+
+	unnamed := filepath.Join(dir, "signing.pem")
+	if err := os.Rename(legacy, unnamed); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readSigningKey(unnamed); err == nil || !strings.Contains(err.Error(), "date de création") {
+		t.Errorf("readSigningKey(%s) error = %v, want a missing creation date", unnamed, err)
+	}
+}
+
+func TestGenerateSigningKeyInTheSameSecond(t *testing.T) {
+	dir := t.TempDir()
+	first, err := generateSigningKey(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := generateSigningKey(dir)
+	if err != nil {
+		t.Fatalf("second generateSigningKey() error = %v", err)
+	}
+	if first.ID == second.ID {
+		t.Errorf("both keys have the kid %s", first.ID)
+	}
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// GenerateRandomToken retourne un jeton aléatoire impossible à deviner, utilisable dans une URL
+func GenerateRandomToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// HashToken retourne l'empreinte SHA-256 d'un jeton, seule valeur conservée en base
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// APITokenPrefix distingue les jetons d'accès personnels des jetons JWT
+const APITokenPrefix = "tm_pat_"
+
+// GenerateAPIToken retourne un nouveau jeton d'accès personnel
+func GenerateAPIToken() (string, error) {
+	token, err := GenerateRandomToken()
+	if err != nil {
+		return "", err
+	}
+	return APITokenPrefix + token, nil
+}
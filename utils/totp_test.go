@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// Secret des vecteurs de test de la RFC 6238 (SHA1) : "12345678901234567890" en base32
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	// Vecteurs de la RFC 6238, annexe B, réduits aux 6 derniers chiffres
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		got, err := TOTPCode(rfc6238Secret, TOTPStep(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("TOTPCode(%d) error = %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("TOTPCode(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	step := TOTPStep(now)
+	code := func(step int64) string {
+		code, err := TOTPCode(rfc6238Secret, step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+
+	tests := []struct {
+		name     string
+		code     string
+		lastStep int64
+		wantStep int64
+		wantOK   bool
+	}{
+		{name: "current period", code: code(step), wantStep: step, wantOK: true},
+		{name: "with spaces", code: " 050 471 ", wantStep: step, wantOK: true},
+		{name: "previous period (clock skew)", code: code(step - 1), wantStep: step - 1, wantOK: true},
+		{name: "next period (clock skew)", code: code(step + 1), wantStep: step + 1, wantOK: true},
+		{name: "outside the skew", code: code(step - 2)},
+		{name: "wrong code", code: "000000"},
+		{name: "too short", code: "05047"},
+		{name: "replay of the last accepted code", code: code(step), lastStep: step},
+		{name: "replay of an older code", code: code(step - 1), lastStep: step},
+		{name: "next code after the last accepted one", code: code(step + 1), lastStep: step, wantStep: step + 1, wantOK: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStep, gotOK := ValidateTOTP(rfc6238Secret, tt.code, now, tt.lastStep)
+			if gotOK != tt.wantOK || gotStep != tt.wantStep {
+				t.Errorf("ValidateTOTP(%q, lastStep %d) = %d, %v, want %d, %v", tt.code, tt.lastStep, gotStep, gotOK, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestValidateTOTPInvalidSecret(t *testing.T) {
+	if _, ok := ValidateTOTP("not base32!", "123456", time.Now(), 0); ok {
+		t.Fatal("ValidateTOTP accepted a code for an invalid secret")
+	}
+}